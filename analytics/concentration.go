@@ -0,0 +1,109 @@
+package analytics
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+// nakamotoThreshold is the share of providers the groups counted by the nakamoto coefficient control.
+const nakamotoThreshold = 0.5
+
+type Report struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	Providers   int           `json:"providers"`
+	Asn         Concentration `json:"asn"`
+	Isp         Concentration `json:"isp"`
+	Country     Concentration `json:"country"`
+}
+
+// Concentration describes how the providers are distributed over the values of one dimension.
+// Providers with an unknown value are left out.
+type Concentration struct {
+	Providers int `json:"providers"`
+	Groups    int `json:"groups"`
+	// HHI is the Herfindahl-Hirschman index as the sum of the squared shares between 0 and 1
+	HHI float64 `json:"hhi"`
+	// Nakamoto is the smallest number of groups that together have more than half of the providers
+	Nakamoto int `json:"nakamoto"`
+	// TopShare is the share of the providers in the largest groups
+	TopShare float64 `json:"top_share"`
+	Top      []Group `json:"top"`
+}
+
+type Group struct {
+	Name      string  `json:"name"`
+	Providers int     `json:"providers"`
+	Share     float64 `json:"share"`
+}
+
+// Compute computes the concentration of the providers per ASN, ISP and country.
+// The top share and groups are computed for the top largest groups.
+func Compute(providers []*proposal.Provider, top int) *Report {
+	asns := make(map[string]int)
+	isps := make(map[string]int)
+	countries := make(map[string]int)
+
+	for _, p := range providers {
+		if p.Location.Asn != 0 {
+			asns[strconv.Itoa(p.Location.Asn)]++
+		}
+		if p.Location.Isp != "" {
+			isps[p.Location.Isp]++
+		}
+		if p.Location.Country != "" {
+			countries[p.Location.Country]++
+		}
+	}
+
+	return &Report{
+		GeneratedAt: time.Now(),
+		Providers:   len(providers),
+		Asn:         concentration(asns, top),
+		Isp:         concentration(isps, top),
+		Country:     concentration(countries, top),
+	}
+}
+
+func concentration(counts map[string]int, top int) Concentration {
+	groups := make([]Group, 0, len(counts))
+	var total int
+	for name, n := range counts {
+		groups = append(groups, Group{Name: name, Providers: n})
+		total += n
+	}
+	slices.SortFunc(groups, func(a, b Group) int {
+		return cmp.Or(cmp.Compare(b.Providers, a.Providers), cmp.Compare(a.Name, b.Name))
+	})
+
+	c := Concentration{
+		Providers: total,
+		Groups:    len(groups),
+		Top:       []Group{},
+	}
+	if total == 0 {
+		return c
+	}
+
+	// the nakamoto coefficient is counted in providers, as summed up shares are not exact
+	var cumulative int
+	for i := range groups {
+		groups[i].Share = float64(groups[i].Providers) / float64(total)
+		c.HHI += groups[i].Share * groups[i].Share
+
+		if float64(cumulative) <= nakamotoThreshold*float64(total) {
+			c.Nakamoto++
+		}
+		cumulative += groups[i].Providers
+
+		if i < top {
+			c.TopShare += groups[i].Share
+			c.Top = append(c.Top, groups[i])
+		}
+	}
+
+	return c
+}
@@ -0,0 +1,109 @@
+package analytics
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func TestConcentration(t *testing.T) {
+	tests := []struct {
+		name     string
+		counts   map[string]int
+		top      int
+		hhi      float64
+		nakamoto int
+		topShare float64
+		topNames []string
+	}{
+		{"empty", map[string]int{}, 3, 0, 0, 0, []string{}},
+		{"monopoly", map[string]int{"a": 10}, 3, 1, 1, 1, []string{"a"}},
+		{"two equal", map[string]int{"a": 5, "b": 5}, 1, 0.5, 2, 0.5, []string{"a"}},
+		{"four equal", map[string]int{"a": 1, "b": 1, "c": 1, "d": 1}, 2, 0.25, 3, 0.5, []string{"a", "b"}},
+		// a group with exactly half of the providers does not control more than half
+		{"half", map[string]int{"a": 2, "b": 1, "c": 1}, 1, 0.375, 2, 0.5, []string{"a"}},
+		{"majority", map[string]int{"a": 6, "b": 2, "c": 2}, 2, 0.44, 1, 0.8, []string{"a", "b"}},
+		{"ordered by size and name", map[string]int{"c": 2, "b": 1, "a": 1, "d": 6}, 3, 0.42, 1, 0.9, []string{"d", "c", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := concentration(tt.counts, tt.top)
+
+			if math.Abs(c.HHI-tt.hhi) > 1e-9 {
+				t.Errorf("HHI = %v, want %v", c.HHI, tt.hhi)
+			}
+			if c.Nakamoto != tt.nakamoto {
+				t.Errorf("Nakamoto = %d, want %d", c.Nakamoto, tt.nakamoto)
+			}
+			if math.Abs(c.TopShare-tt.topShare) > 1e-9 {
+				t.Errorf("TopShare = %v, want %v", c.TopShare, tt.topShare)
+			}
+			if c.Groups != len(tt.counts) {
+				t.Errorf("Groups = %d, want %d", c.Groups, len(tt.counts))
+			}
+
+			names := make([]string, 0, len(c.Top))
+			for _, g := range c.Top {
+				names = append(names, g.Name)
+			}
+			if !slices.Equal(names, tt.topNames) {
+				t.Errorf("Top = %v, want %v", names, tt.topNames)
+			}
+		})
+	}
+}
+
+func TestConcentrationEqualGroups(t *testing.T) {
+	for n := 1; n <= 100; n++ {
+		counts := make(map[string]int, n)
+		for i := range n {
+			counts[strconv.Itoa(i)] = 3
+		}
+
+		// exactly half of the providers is not a majority
+		want := n/2 + 1
+		if got := concentration(counts, 1).Nakamoto; got != want {
+			t.Errorf("%d equal groups: Nakamoto = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	providers := []*proposal.Provider{
+		{ID: "0xaaa", Location: proposal.Location{Country: "DE", Asn: 3320, Isp: "DTAG"}},
+		{ID: "0xbbb", Location: proposal.Location{Country: "DE", Asn: 3320, Isp: "DTAG"}},
+		{ID: "0xccc", Location: proposal.Location{Country: "US", Asn: 16509}},
+		{ID: "0xddd", Location: proposal.Location{}},
+	}
+
+	r := Compute(providers, 1)
+
+	tests := []struct {
+		name      string
+		c         Concentration
+		providers int
+		groups    int
+		top       string
+	}{
+		{"asn", r.Asn, 3, 2, "3320"},
+		{"isp", r.Isp, 2, 1, "DTAG"},
+		{"country", r.Country, 3, 2, "DE"},
+	}
+
+	if r.Providers != len(providers) {
+		t.Errorf("Providers = %d, want %d", r.Providers, len(providers))
+	}
+	// providers with unknown values are left out of the dimension
+	for _, tt := range tests {
+		if tt.c.Providers != tt.providers || tt.c.Groups != tt.groups {
+			t.Errorf("%s: %d providers in %d groups, want %d in %d", tt.name, tt.c.Providers, tt.c.Groups, tt.providers, tt.groups)
+		}
+		if len(tt.c.Top) != 1 || tt.c.Top[0].Name != tt.top {
+			t.Errorf("%s: top = %+v, want %s", tt.name, tt.c.Top, tt.top)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package analytics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+// Service recomputes the concentration report of the repository on an interval.
+type Service struct {
+	repository *proposal.Repository
+	interval   time.Duration
+	top        int
+	report     atomic.Pointer[Report]
+	stopCh     chan struct{}
+	waitGroup  sync.WaitGroup
+}
+
+func NewAnalyticsService(repository *proposal.Repository, interval time.Duration, top int) *Service {
+	return &Service{
+		repository: repository,
+		interval:   interval,
+		top:        top,
+		stopCh:     make(chan struct{}),
+	}
+}
+
+func (s *Service) Start() {
+	log.Debug().Msg("Starting analytics service")
+	s.update()
+	s.waitGroup.Add(1)
+	go s.run()
+}
+
+func (s *Service) Stop() {
+	close(s.stopCh)
+	s.waitGroup.Wait()
+}
+
+// Report returns the last computed report.
+func (s *Service) Report() *Report {
+	return s.report.Load()
+}
+
+// Top is the number of largest groups the top share is computed for.
+func (s *Service) Top() int {
+	return s.top
+}
+
+// Interval is the duration between the reports.
+func (s *Service) Interval() time.Duration {
+	return s.interval
+}
+
+func (s *Service) run() {
+	defer s.waitGroup.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+
+		case <-ticker.C:
+			s.update()
+		}
+	}
+}
+
+func (s *Service) update() {
+	s.report.Store(Compute(s.repository.Providers(), s.top))
+}
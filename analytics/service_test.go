@@ -0,0 +1,36 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func TestService(t *testing.T) {
+	r := proposal.NewProposalRepository(time.Hour)
+	r.Store(&proposal.Proposal{ProviderID: "0xaaa", ServiceType: "wireguard", Location: proposal.Location{Country: "DE"}})
+
+	s := NewAnalyticsService(r, 10*time.Millisecond, 3)
+	if s.Report() != nil {
+		t.Fatal("report exists before the service was started")
+	}
+
+	// the first report is computed by Start
+	s.Start()
+	defer s.Stop()
+	first := s.Report()
+	if first == nil || first.Providers != 1 {
+		t.Fatalf("report after Start = %+v, want one provider", first)
+	}
+
+	r.Store(&proposal.Proposal{ProviderID: "0xbbb", ServiceType: "wireguard", Location: proposal.Location{Country: "US"}})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Report().Providers != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("report was not recomputed on the interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
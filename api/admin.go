@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sch8ill/propmon/metrics"
+	"github.com/sch8ill/propmon/proposal"
+	"github.com/sch8ill/propmon/proposal/expiration"
+	"github.com/sch8ill/propmon/quality"
+)
+
+var errNotFound = errors.New("not found")
+
+type adminHandler struct {
+	repository *proposal.Repository
+	quality    *quality.Service
+	expiration *expiration.Service
+	watchlist  *metrics.Watchlist
+	audit      *auditLog
+	started    time.Time
+}
+
+func newAdminHandler(repository *proposal.Repository, quality *quality.Service, expiration *expiration.Service, watchlist *metrics.Watchlist, audit *auditLog) *adminHandler {
+	return &adminHandler{
+		repository: repository,
+		quality:    quality,
+		expiration: expiration,
+		watchlist:  watchlist,
+		audit:      audit,
+		started:    time.Now(),
+	}
+}
+
+func (h *adminHandler) getStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"uptime":    time.Since(h.started).Round(time.Second).String(),
+		"proposals": h.repository.CountProposals(),
+		"providers": h.repository.CountProviders(),
+	})
+}
+
+func (h *adminHandler) getDebug(c *gin.Context) {
+	h.audit.record(c, "dump_debug_stats", "", nil)
+	c.JSON(http.StatusOK, h.repository.DebugStats())
+}
+
+func (h *adminHandler) evictProvider(c *gin.Context) {
+	id := c.Param("id")
+	removed := h.repository.RemoveProvider(id)
+
+	if removed == 0 {
+		h.audit.record(c, "evict_provider", id, errNotFound)
+		c.JSON(http.StatusNotFound, gin.H{"error": "provider not found"})
+		return
+	}
+
+	h.audit.record(c, "evict_provider", id, nil)
+	c.JSON(http.StatusOK, gin.H{"removed": removed})
+}
+
+func (h *adminHandler) pinProposal(c *gin.Context) {
+	h.setPinned(c, true)
+}
+
+func (h *adminHandler) unpinProposal(c *gin.Context) {
+	h.setPinned(c, false)
+}
+
+func (h *adminHandler) setPinned(c *gin.Context, pinned bool) {
+	action := "pin_proposal"
+	if !pinned {
+		action = "unpin_proposal"
+	}
+
+	key := c.Param("key")
+	if !h.repository.SetPinned(key, pinned) {
+		h.audit.record(c, action, key, errNotFound)
+		c.JSON(http.StatusNotFound, gin.H{"error": "proposal not found"})
+		return
+	}
+
+	h.audit.record(c, action, key, nil)
+	c.JSON(http.StatusOK, gin.H{"key": key, "pinned": pinned})
+}
+
+func (h *adminHandler) refreshQuality(c *gin.Context) {
+	entries, err := h.quality.Refresh()
+	h.audit.record(c, "refresh_quality", "", err)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+func (h *adminHandler) sweepExpired(c *gin.Context) {
+	expired := h.expiration.Sweep()
+	h.audit.record(c, "sweep_expired", "", nil)
+	c.JSON(http.StatusOK, gin.H{"expired": expired})
+}
+
+func (h *adminHandler) getWatchlist(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.watchlist.IDs()})
+}
+
+func (h *adminHandler) watchProvider(c *gin.Context) {
+	id := c.Param("id")
+	added := h.watchlist.Add(id)
+	h.audit.record(c, "watch_provider", id, nil)
+	c.JSON(http.StatusOK, gin.H{"id": id, "added": added})
+}
+
+func (h *adminHandler) unwatchProvider(c *gin.Context) {
+	id := c.Param("id")
+	if !h.watchlist.Remove(id) {
+		h.audit.record(c, "unwatch_provider", id, errNotFound)
+		c.JSON(http.StatusNotFound, gin.H{"error": "provider not watched"})
+		return
+	}
+
+	h.audit.record(c, "unwatch_provider", id, nil)
+	c.JSON(http.StatusOK, gin.H{"id": id})
+}
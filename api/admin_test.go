@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sch8ill/propmon/proposal"
+	"github.com/sch8ill/propmon/proposal/expiration"
+	"github.com/sch8ill/propmon/quality"
+)
+
+func newTestAdmin(t *testing.T, repository *proposal.Repository, oracleURL string) (http.Handler, string) {
+	t.Helper()
+
+	audit := filepath.Join(t.TempDir(), "audit.log")
+	options := Options{
+		Admin:    Listener{Address: ":9091"},
+		APIKeys:  testAPIKeys(),
+		AuditLog: audit,
+	}
+
+	a := New(repository, nil, quality.NewQualityService(quality.NewOracle(oracleURL), repository, time.Hour, time.Hour),
+		expiration.NewExpirationService(repository, time.Hour), options)
+	if err := a.register(); err != nil {
+		t.Fatal(err)
+	}
+
+	return a.servers[":9091"].engine, audit
+}
+
+func TestAdminOperations(t *testing.T) {
+	oracle := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"0xaaa": {"quality": 3, "latency": 10, "bandwidth": 50, "uptime": 24}}`))
+	}))
+	defer oracle.Close()
+
+	repository := proposal.NewProposalRepository(time.Millisecond)
+	for _, p := range testProposals() {
+		repository.Store(p)
+	}
+	h, audit := newTestAdmin(t, repository, oracle.URL)
+	admin := http.Header{"X-Api-Key": {"admin-key"}}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header http.Header
+		status int
+		body   string
+	}{
+		{"no key", http.MethodGet, "/admin/status", nil, http.StatusUnauthorized, ""},
+		{"no admin scope", http.MethodGet, "/admin/status", http.Header{"X-Api-Key": {"read-key"}}, http.StatusForbidden, ""},
+		{"status", http.MethodGet, "/admin/status", admin, http.StatusOK, `"providers":2`},
+		{"pin", http.MethodPut, "/admin/proposals/0xaaa.wireguard/pin", admin, http.StatusOK, `"pinned":true`},
+		{"pin unknown", http.MethodPut, "/admin/proposals/0xccc.wireguard/pin", admin, http.StatusNotFound, ""},
+		{"refresh quality", http.MethodPost, "/admin/quality/refresh", admin, http.StatusOK, `{"entries":1}`},
+		// every proposal but the pinned one has expired by now
+		{"sweep", http.MethodPost, "/admin/expiration/sweep", admin, http.StatusOK, `{"expired":2}`},
+		{"debug", http.MethodGet, "/admin/debug", admin, http.StatusOK, `"pinned_proposals":["0xaaa.wireguard"]`},
+		{"unpin", http.MethodDelete, "/admin/proposals/0xaaa.wireguard/pin", admin, http.StatusOK, `"pinned":false`},
+		{"evict", http.MethodDelete, "/admin/providers/0xaaa", admin, http.StatusOK, `{"removed":1}`},
+		{"evict unknown", http.MethodDelete, "/admin/providers/0xaaa", admin, http.StatusNotFound, ""},
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	for _, tt := range tests {
+		rec := serve(h, tt.method, tt.path, tt.header)
+		if rec.Code != tt.status {
+			t.Fatalf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.status, rec.Body)
+		}
+		if !strings.Contains(rec.Body.String(), tt.body) {
+			t.Errorf("%s: body = %s, want %s", tt.name, rec.Body, tt.body)
+		}
+	}
+
+	if q := repository.Get("0xaaa.wireguard"); q != nil {
+		t.Error("evicted proposal is still stored")
+	}
+
+	data, err := os.ReadFile(audit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("%d audit records, want 8", len(lines))
+	}
+
+	var record struct {
+		Action string `json:"action"`
+		Target string `json:"target"`
+		Key    string `json:"key"`
+		Level  string `json:"level"`
+	}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Action != "evict_provider" || record.Target != "0xaaa" || record.Key != "admin" || record.Level != "warn" {
+		t.Errorf("last audit record = %+v", record)
+	}
+}
+
+func TestAdminRefreshQualityFailure(t *testing.T) {
+	oracle := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("not json"))
+	}))
+	defer oracle.Close()
+
+	h, _ := newTestAdmin(t, proposal.NewProposalRepository(time.Hour), oracle.URL)
+
+	rec := serve(h, http.MethodPost, "/admin/quality/refresh", http.Header{"X-Api-Key": {"admin-key"}})
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", rec.Code)
+	}
+}
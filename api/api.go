@@ -1,42 +1,286 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"io"
-	"time"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
 
+	"github.com/sch8ill/propmon/analytics"
+	"github.com/sch8ill/propmon/auth"
+	"github.com/sch8ill/propmon/config"
+	"github.com/sch8ill/propmon/geo"
 	"github.com/sch8ill/propmon/metrics"
 	"github.com/sch8ill/propmon/proposal"
+	"github.com/sch8ill/propmon/proposal/expiration"
+	"github.com/sch8ill/propmon/quality"
+	"github.com/sch8ill/propmon/stats"
 )
 
+const (
+	apiGroup       = "api"
+	discoveryGroup = "discovery"
+	metricsGroup   = "metrics"
+	adminGroup     = "admin"
+)
+
+// Listener configures the address a surface of the api is served on. TLS is enabled if
+// a certificate and key are set.
+type Listener struct {
+	Address string
+	TLSCert string
+	TLSKey  string
+}
+
+func (l Listener) tls() bool {
+	return l.TLSCert != "" || l.TLSKey != ""
+}
+
+type Options struct {
+	// Metrics serves the prometheus endpoint
+	Metrics Listener
+	// API serves the public api, it shares the metrics listener if no address is set
+	API Listener
+	// Admin serves the admin api, which is disabled if no address is set
+	Admin               Listener
+	EventBufferSize     int
+	RateLimits          map[string]config.RateLimit
+	RateLimitMaxClients int
+	TrustedProxies      []string
+	APIKeys             []auth.APIKey
+	// MetricsAllow lists addresses and cidr ranges that may scrape metrics without an api key
+	MetricsAllow []string
+	// AuditLog is the file admin operations are recorded in
+	AuditLog string
+	// Compression enables zstd and gzip compression of responses
+	Compression bool
+	// GeoCentroids is a csv file of centroids that extends the bundled dataset
+	GeoCentroids string
+	// Watchlist holds the providers exported individually, it can be changed with the admin api
+	Watchlist *metrics.Watchlist
+	// Analytics serves the network concentration report, which is disabled if not set
+	Analytics *analytics.Service
+}
+
 type API struct {
-	address    string
+	options    Options
 	repository *proposal.Repository
+	stats      *stats.Cache
+	quality    *quality.Service
+	expiration *expiration.Service
+	auth       *authenticator
+	servers    map[string]*server
+}
+
+type server struct {
+	listener Listener
+	engine   *gin.Engine
 }
 
-func New(address string, repository *proposal.Repository) *API {
+func New(repository *proposal.Repository, stats *stats.Cache, quality *quality.Service, expiration *expiration.Service, options Options) *API {
 	return &API{
-		address:    address,
+		options:    options,
 		repository: repository,
+		stats:      stats,
+		quality:    quality,
+		expiration: expiration,
+		servers:    make(map[string]*server),
 	}
 }
 
+// Run serves the metrics, public and admin api on their listeners until one of them fails.
 func (a *API) Run() error {
 	gin.DefaultWriter = io.Discard
 	gin.SetMode(gin.ReleaseMode)
 
-	r := gin.Default()
+	if err := a.register(); err != nil {
+		return err
+	}
+	a.validateOpenAPI()
+
+	errCh := make(chan error, len(a.servers))
+	for _, s := range a.servers {
+		go func() {
+			errCh <- s.run()
+		}()
+	}
+
+	return <-errCh
+}
+
+// register sets up the routers of every configured surface.
+func (a *API) register() error {
+	var err error
+	if a.auth, err = newAuthenticator(a.options.APIKeys); err != nil {
+		return fmt.Errorf("invalid api keys: %w", err)
+	}
 
-	r.GET("/metrics", gin.WrapH(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
+	if err := a.registerMetrics(); err != nil {
+		return err
+	}
+	if err := a.registerAPI(); err != nil {
+		return err
+	}
+	if a.options.Admin.Address != "" {
+		if err := a.registerAdmin(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *API) registerMetrics() error {
+	r, err := a.engine(a.options.Metrics)
+	if err != nil {
+		return err
+	}
+
+	metricsAllow, err := parsePrefixes(a.options.MetricsAllow)
+	if err != nil {
+		return fmt.Errorf("invalid metrics allow list: %w", err)
+	}
 
+	r.GET("/metrics", a.rateLimit(metricsGroup), a.auth.authorize(auth.ScopeReadMetrics, metricsAllow...),
+		gin.WrapH(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
+
+	return nil
+}
+
+func (a *API) registerAPI() error {
+	listener := a.options.API
+	if listener.Address == "" {
+		listener = a.options.Metrics
+	}
+
+	r, err := a.engine(listener)
+	if err != nil {
+		return err
+	}
+
+	apiLimit := a.rateLimit(apiGroup)
 	api := r.Group("/api/v1")
-	rateLimiter := newRateLimiter(20, time.Minute)
-	api.Use(rateLimiter.middleware())
+	api.Use(apiLimit)
 
-	handler := newHandler(a.repository)
-	api.GET("/proposals", handler.getProposals)
+	locator, err := geo.NewLocator()
+	if err != nil {
+		return err
+	}
+	if a.options.GeoCentroids != "" {
+		if err := locator.Load(a.options.GeoCentroids); err != nil {
+			return err
+		}
+	}
+
+	handler := newHandler(a.repository, a.stats, a.options.Analytics, locator, a.options.EventBufferSize)
+	readProposals := a.auth.authorize(auth.ScopeReadProposals)
+	api.GET("/proposals", readProposals, handler.getProposals)
+	api.GET("/stats", readProposals, handler.getStats)
+	api.GET("/events", readProposals, handler.getEvents)
+	api.GET("/export", a.auth.authorize(auth.ScopeReadExport), handler.getExport)
+	api.GET("/providers.geojson", readProposals, handler.getProvidersGeoJSON)
+	api.GET("/countries.geojson", readProposals, handler.getCountriesGeoJSON)
+	api.GET("/openapi.json", getOpenAPI)
+	if a.options.Analytics != nil {
+		api.GET("/concentration", readProposals, handler.getConcentration)
+	}
+
+	graphQL := serveGraphQL(newGraphQLSchema(a.repository))
+	r.GET("/graphql", apiLimit, readProposals, graphQL)
+	r.POST("/graphql", apiLimit, readProposals, graphQL)
+
+	discovery := r.Group("/api/v4")
+	discovery.Use(a.rateLimit(discoveryGroup))
+	discovery.GET("/proposals", readProposals, handler.getDiscoveryProposals)
+
+	return nil
+}
+
+func (a *API) registerAdmin() error {
+	if !a.auth.keyring.HasScope(auth.ScopeAdmin) {
+		return errors.New("the admin api requires an api key with the admin scope")
+	}
+
+	r, err := a.engine(a.options.Admin)
+	if err != nil {
+		return err
+	}
+
+	audit, err := newAuditLog(a.options.AuditLog)
+	if err != nil {
+		return err
+	}
+
+	admin := r.Group("/admin")
+	admin.Use(a.rateLimit(adminGroup), a.auth.require(auth.ScopeAdmin))
+
+	handler := newAdminHandler(a.repository, a.quality, a.expiration, a.options.Watchlist, audit)
+	admin.GET("/status", handler.getStatus)
+	admin.GET("/debug", handler.getDebug)
+	admin.DELETE("/providers/:id", handler.evictProvider)
+	admin.PUT("/proposals/:key/pin", handler.pinProposal)
+	admin.DELETE("/proposals/:key/pin", handler.unpinProposal)
+	admin.POST("/quality/refresh", handler.refreshQuality)
+	admin.POST("/expiration/sweep", handler.sweepExpired)
+	if a.options.Watchlist != nil {
+		admin.GET("/watchlist", handler.getWatchlist)
+		admin.PUT("/watchlist/:id", handler.watchProvider)
+		admin.DELETE("/watchlist/:id", handler.unwatchProvider)
+	}
+
+	return nil
+}
+
+// engine returns the router serving the listener. Surfaces configured with the same address
+// share a router and must agree on the tls configuration.
+func (a *API) engine(listener Listener) (*gin.Engine, error) {
+	if s, ok := a.servers[listener.Address]; ok {
+		if s.listener != listener {
+			return nil, fmt.Errorf("conflicting tls configuration for %s", listener.Address)
+		}
+		return s.engine, nil
+	}
+
+	if listener.tls() && (listener.TLSCert == "" || listener.TLSKey == "") {
+		return nil, fmt.Errorf("tls for %s requires a certificate and a key", listener.Address)
+	}
+
+	r := gin.Default()
+	if err := r.SetTrustedProxies(a.options.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	r.Use(a.auth.authenticate())
+	if a.options.Compression {
+		r.Use(compress())
+	}
+
+	a.servers[listener.Address] = &server{listener: listener, engine: r}
+	return r, nil
+}
+
+func (s *server) run() error {
+	log.Info().Str("addr", s.listener.Address).Bool("tls", s.listener.tls()).Msg("Starting http listener")
+
+	srv := &http.Server{
+		Addr:    s.listener.Address,
+		Handler: s.engine.Handler(),
+	}
+
+	if s.listener.tls() {
+		return srv.ListenAndServeTLS(s.listener.TLSCert, s.listener.TLSKey)
+	}
+	return srv.ListenAndServe()
+}
+
+func (a *API) rateLimit(group string) gin.HandlerFunc {
+	limit := a.options.RateLimits[group]
+	if limit.Requests == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
 
-	return r.Run(a.address)
+	return newRateLimiter(group, limit, a.options.RateLimitMaxClients).middleware()
 }
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/sch8ill/propmon/auth"
+	"github.com/sch8ill/propmon/proposal"
+	"github.com/sch8ill/propmon/stats"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	log.Logger = zerolog.Nop()
+}
+
+func testProposals() []*proposal.Proposal {
+	return []*proposal.Proposal{
+		{
+			Format:        "service-proposal/v3",
+			Compatibility: 2,
+			ProviderID:    "0xaaa",
+			ServiceType:   "wireguard",
+			Location:      proposal.Location{Continent: "EU", Country: "DE", City: "Berlin", Asn: 3320, Isp: "DTAG", IpType: "residential"},
+			Contacts:      []proposal.Contact{{Type: "nats/p2p/v1"}},
+			Quality:       &proposal.Quality{Quality: 2.5, Latency: 40, Bandwidth: 80, Uptime: 20},
+		},
+		{
+			Format:         "service-proposal/v3",
+			Compatibility:  2,
+			ProviderID:     "0xbbb",
+			ServiceType:    "wireguard",
+			Location:       proposal.Location{Continent: "NA", Country: "US", City: "Ashburn", Asn: 16509, Isp: "Amazon", IpType: "hosting"},
+			AccessPolicies: []proposal.AccessPolicy{{ID: "mysterium", Source: "https://trust.mysterium.network/api/v1/access-policies/mysterium"}},
+		},
+		{
+			Format:        "service-proposal/v3",
+			Compatibility: 1,
+			ProviderID:    "0xaaa",
+			ServiceType:   "openvpn",
+			Location:      proposal.Location{Continent: "EU", Country: "DE", City: "Berlin", Asn: 3320, Isp: "DTAG", IpType: "residential"},
+			Quality:       &proposal.Quality{Quality: 1, Latency: 90, Bandwidth: 20, Uptime: 10},
+		},
+	}
+}
+
+// newTestAPI registers the metrics and public api on a single router.
+func newTestAPI(t *testing.T, options Options, proposals ...*proposal.Proposal) (*API, http.Handler) {
+	t.Helper()
+
+	repository := proposal.NewProposalRepository(time.Hour)
+	for _, p := range proposals {
+		repository.Store(p)
+	}
+
+	a := New(repository, stats.NewCache(repository, time.Minute), nil, nil, options)
+	if err := a.register(); err != nil {
+		t.Fatal(err)
+	}
+
+	return a, a.servers[options.Metrics.Address].engine
+}
+
+func serve(h http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestListeners(t *testing.T) {
+	options := Options{
+		Metrics: Listener{Address: ":9090"},
+		API:     Listener{Address: ":8080"},
+		Admin:   Listener{Address: ":9091"},
+		APIKeys: testAPIKeys(),
+	}
+
+	a := New(proposal.NewProposalRepository(time.Hour), nil, nil, nil, options)
+	if err := a.register(); err != nil {
+		t.Fatal(err)
+	}
+	if len(a.servers) != 3 {
+		t.Fatalf("%d servers, want 3", len(a.servers))
+	}
+
+	admin := http.Header{"X-Api-Key": {"admin-key"}}
+	tests := []struct {
+		address string
+		path    string
+		status  int
+	}{
+		{":9090", "/metrics", http.StatusOK},
+		{":9090", "/api/v1/proposals", http.StatusNotFound},
+		{":8080", "/api/v1/proposals", http.StatusOK},
+		{":8080", "/metrics", http.StatusNotFound},
+		{":8080", "/admin/status", http.StatusNotFound},
+		{":9091", "/admin/status", http.StatusOK},
+		{":9091", "/api/v1/proposals", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		if rec := serve(a.servers[tt.address].engine, http.MethodGet, tt.path, admin); rec.Code != tt.status {
+			t.Errorf("%s%s: status = %d, want %d", tt.address, tt.path, rec.Code, tt.status)
+		}
+	}
+}
+
+func TestListenerErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+	}{
+		{"admin without admin key", Options{
+			Admin:   Listener{Address: ":9091"},
+			APIKeys: []auth.APIKey{{Name: "reader", Key: "k", Scopes: []auth.Scope{auth.ScopeReadProposals}}},
+		}},
+		{"conflicting tls", Options{
+			Metrics: Listener{Address: ":8080"},
+			API:     Listener{Address: ":8080", TLSCert: "cert.pem", TLSKey: "key.pem"},
+		}},
+		{"certificate without key", Options{
+			API: Listener{Address: ":8443", TLSCert: "cert.pem"},
+		}},
+		{"invalid metrics allow list", Options{MetricsAllow: []string{"localhost"}}},
+	}
+
+	for _, tt := range tests {
+		a := New(proposal.NewProposalRepository(time.Hour), nil, nil, nil, tt.options)
+		if err := a.register(); err == nil {
+			t.Errorf("%s: register succeeded, want an error", tt.name)
+		}
+	}
+}
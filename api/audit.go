@@ -0,0 +1,44 @@
+package api
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// auditLog records admin operations as json lines in a file, or in the application log if no
+// file is configured.
+type auditLog struct {
+	logger zerolog.Logger
+}
+
+func newAuditLog(path string) (*auditLog, error) {
+	if path == "" {
+		return &auditLog{logger: log.Logger.With().Str("log", "audit").Logger()}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	return &auditLog{logger: zerolog.New(file).With().Timestamp().Logger()}, nil
+}
+
+// record logs an admin action performed by the client of the request.
+func (a *auditLog) record(c *gin.Context, action string, target string, err error) {
+	event := a.logger.Info()
+	if err != nil {
+		event = a.logger.Warn().Err(err)
+	}
+
+	event.
+		Str("action", action).
+		Str("target", target).
+		Str("key", c.GetString(apiKeyContextKey)).
+		Str("client", c.ClientIP()).
+		Msg("Admin operation")
+}
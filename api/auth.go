@@ -0,0 +1,150 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sch8ill/propmon/auth"
+	"github.com/sch8ill/propmon/metrics"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	// apiKeyContextKey holds the name of the api key an authenticated request was made with
+	apiKeyContextKey = "propmon.api_key"
+	// authKeyContextKey holds the *APIKey an authenticated request was made with
+	authKeyContextKey = "propmon.auth_key"
+)
+
+// authenticator checks api keys sent as bearer token or X-API-Key header.
+// Without any configured keys, authentication is disabled and every request is allowed.
+type authenticator struct {
+	keyring *auth.Keyring
+}
+
+func newAuthenticator(keys []auth.APIKey) (*authenticator, error) {
+	keyring, err := auth.NewKeyring(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authenticator{keyring: keyring}, nil
+}
+
+func (a *authenticator) enabled() bool {
+	return !a.keyring.Empty()
+}
+
+// authenticate identifies the api key of a request, if it carries one. Requests with an unknown
+// key are rejected. While authentication is disabled, keys sent by clients are ignored.
+func (a *authenticator) authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !a.enabled() {
+			c.Next()
+			return
+		}
+
+		token := requestAPIKey(c)
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		key, ok := a.keyring.Lookup(token)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+			return
+		}
+
+		c.Set(apiKeyContextKey, key.Name)
+		c.Set(authKeyContextKey, key)
+		metrics.APIKeyRequest(key.Name)
+		c.Next()
+	}
+}
+
+// authorize requires the api key of the request to grant the scope. Clients from the exempt
+// networks and all clients while authentication is disabled are always allowed.
+func (a *authenticator) authorize(scope auth.Scope, exempt ...netip.Prefix) gin.HandlerFunc {
+	require := a.require(scope)
+
+	return func(c *gin.Context) {
+		if !a.enabled() {
+			c.Next()
+			return
+		}
+
+		if len(exempt) > 0 {
+			if addr, err := netip.ParseAddr(c.ClientIP()); err == nil {
+				addr = addr.Unmap()
+				if slices.ContainsFunc(exempt, func(p netip.Prefix) bool { return p.Contains(addr) }) {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		require(c)
+	}
+}
+
+// require requires the api key of the request to grant the scope, even if authentication is disabled.
+func (a *authenticator) require(scope auth.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(authKeyContextKey)
+		if !ok {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "api key required"})
+			return
+		}
+
+		if !value.(*auth.APIKey).HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("api key lacks scope %s", scope)})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func requestAPIKey(c *gin.Context) string {
+	if key := c.GetHeader(apiKeyHeader); key != "" {
+		return key
+	}
+
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+
+	return ""
+}
+
+// parsePrefixes parses ip addresses and cidr ranges.
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q: %w", value, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr range %q: %w", value, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/sch8ill/propmon/auth"
+)
+
+func testAPIKeys() []auth.APIKey {
+	hash := sha256.Sum256([]byte("export-key"))
+	return []auth.APIKey{
+		{Name: "reader", Key: "read-key", Scopes: []auth.Scope{auth.ScopeReadProposals}},
+		{Name: "exporter", KeySHA256: hex.EncodeToString(hash[:]), Scopes: []auth.Scope{auth.ScopeReadExport}},
+		{Name: "admin", Key: "admin-key", Scopes: []auth.Scope{auth.ScopeAdmin}},
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	_, h := newTestAPI(t, Options{APIKeys: testAPIKeys(), MetricsAllow: []string{"198.51.100.0/24"}})
+
+	tests := []struct {
+		name   string
+		path   string
+		header http.Header
+		status int
+	}{
+		{"no key", "/api/v1/proposals", nil, http.StatusUnauthorized},
+		{"invalid key", "/api/v1/proposals", http.Header{"X-Api-Key": {"wrong"}}, http.StatusUnauthorized},
+		{"scope granted", "/api/v1/proposals", http.Header{"X-Api-Key": {"read-key"}}, http.StatusOK},
+		{"bearer token", "/api/v1/proposals", http.Header{"Authorization": {"Bearer read-key"}}, http.StatusOK},
+		{"scope missing", "/api/v1/export", http.Header{"X-Api-Key": {"read-key"}}, http.StatusForbidden},
+		{"hashed key", "/api/v1/export", http.Header{"X-Api-Key": {"export-key"}}, http.StatusOK},
+		{"admin grants every scope", "/api/v1/export", http.Header{"Authorization": {"bearer admin-key"}}, http.StatusOK},
+		{"graphql", "/graphql?query={proposals{totalCount}}", http.Header{"X-Api-Key": {"export-key"}}, http.StatusForbidden},
+		{"discovery", "/api/v4/proposals", http.Header{"X-Api-Key": {"read-key"}}, http.StatusOK},
+		{"openapi is public", "/api/v1/openapi.json", nil, http.StatusOK},
+		{"metrics without key", "/metrics", nil, http.StatusUnauthorized},
+		{"metrics with admin key", "/metrics", http.Header{"X-Api-Key": {"admin-key"}}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serve(h, http.MethodGet, tt.path, tt.header); rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestMetricsAllow(t *testing.T) {
+	// httptest requests come from 192.0.2.1
+	_, h := newTestAPI(t, Options{APIKeys: testAPIKeys(), MetricsAllow: []string{"192.0.2.0/24"}})
+
+	if rec := serve(h, http.MethodGet, "/metrics", nil); rec.Code != http.StatusOK {
+		t.Errorf("metrics: status = %d, want 200", rec.Code)
+	}
+	if rec := serve(h, http.MethodGet, "/api/v1/proposals", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("proposals: status = %d, want 401", rec.Code)
+	}
+}
+
+func TestAuthenticationDisabled(t *testing.T) {
+	_, h := newTestAPI(t, Options{})
+
+	// clients sending a key to an instance without keys are not rejected
+	headers := []http.Header{
+		nil,
+		{"X-Api-Key": {"anything"}},
+		{"Authorization": {"Bearer anything"}},
+	}
+
+	for _, header := range headers {
+		if rec := serve(h, http.MethodGet, "/api/v1/proposals", header); rec.Code != http.StatusOK {
+			t.Errorf("%v: status = %d, want 200", header, rec.Code)
+		}
+	}
+}
+
+func TestParsePrefixes(t *testing.T) {
+	prefixes, err := parsePrefixes([]string{"10.0.0.1", "::ffff:10.0.0.2", "192.0.2.7/24", "2001:db8::/32"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"10.0.0.1/32", "10.0.0.2/32", "192.0.2.0/24", "2001:db8::/32"}
+	for i, prefix := range prefixes {
+		if prefix.String() != want[i] {
+			t.Errorf("prefix %d = %s, want %s", i, prefix, want[i])
+		}
+	}
+
+	for _, invalid := range []string{"10.0.0", "10.0.0.0/33", "localhost"} {
+		if _, err := parsePrefixes([]string{invalid}); err == nil {
+			t.Errorf("parsePrefixes(%q) succeeded, want an error", invalid)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", encodingGzip},
+		{"zstd", encodingZstd},
+		{"gzip, zstd", encodingZstd},
+		{"GZIP;q=0.5, br", encodingGzip},
+		{"zstd;q=0, gzip", encodingGzip},
+		{"zstd;q=0, gzip;q=0", ""},
+		{"gzip;q=0.0", ""},
+		{"zstd;q=0.1, gzip;q=1", encodingZstd},
+		{"gzip;q=invalid", encodingGzip},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func decode(t *testing.T, encoding string, body []byte) []byte {
+	t.Helper()
+
+	var r io.Reader
+	switch encoding {
+	case "":
+		return body
+	case encodingGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gr
+	case encodingZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		t.Fatalf("unexpected encoding %q", encoding)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func newCompressEngine() *gin.Engine {
+	r := gin.New()
+	r.Use(compress())
+
+	r.GET("/json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": strings.Repeat("propmon ", 100)})
+	})
+	r.GET("/events", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString("data: {}\n\n")
+	})
+	r.GET("/not-modified", func(c *gin.Context) {
+		c.Status(http.StatusNotModified)
+	})
+	r.GET("/binary", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/octet-stream", []byte("binary"))
+	})
+	r.GET("/encoded", func(c *gin.Context) {
+		// handlers like promhttp compress their responses themselves
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		_, _ = gw.Write([]byte(`{"encoded":true}`))
+		_ = gw.Close()
+		c.Header("Content-Encoding", encodingGzip)
+		c.Data(http.StatusOK, "application/json", buf.Bytes())
+	})
+	r.GET("/ws", func(c *gin.Context) {
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"upgraded":true}`))
+	})
+
+	return r
+}
+
+func TestCompress(t *testing.T) {
+	h := newCompressEngine()
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		status         int
+		encoding       string
+		body           string
+	}{
+		{"zstd preferred", "/json", "gzip, zstd", http.StatusOK, encodingZstd, ""},
+		{"gzip", "/json", "gzip", http.StatusOK, encodingGzip, ""},
+		{"refused zstd", "/json", "zstd;q=0, gzip", http.StatusOK, encodingGzip, ""},
+		{"nothing accepted", "/json", "gzip;q=0", http.StatusOK, "", ""},
+		{"no header", "/json", "", http.StatusOK, "", ""},
+		{"event stream", "/events", "gzip, zstd", http.StatusOK, "", "data: {}\n\n"},
+		{"not modified", "/not-modified", "gzip, zstd", http.StatusNotModified, "", ""},
+		{"binary", "/binary", "gzip, zstd", http.StatusOK, "", "binary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(h, http.MethodGet, tt.path, http.Header{"Accept-Encoding": {tt.acceptEncoding}})
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.encoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.encoding)
+			}
+			if rec.Header().Get("Vary") != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", rec.Header().Get("Vary"))
+			}
+
+			body := string(decode(t, tt.encoding, rec.Body.Bytes()))
+			if tt.path == "/json" && !strings.Contains(body, "propmon propmon") {
+				t.Errorf("body = %q", body)
+			}
+			if tt.body != "" && body != tt.body {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+			if tt.status == http.StatusNotModified && rec.Body.Len() > 0 {
+				t.Errorf("304 response has a body: %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCompressEncodedResponse(t *testing.T) {
+	rec := serve(newCompressEngine(), http.MethodGet, "/encoded", http.Header{"Accept-Encoding": {"zstd, gzip"}})
+
+	if got := rec.Header().Get("Content-Encoding"); got != encodingGzip {
+		t.Fatalf("Content-Encoding = %q, want the encoding of the handler", got)
+	}
+	// the response is only encoded once
+	if body := decode(t, encodingGzip, rec.Body.Bytes()); string(body) != `{"encoded":true}` {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestCompressMetrics(t *testing.T) {
+	_, h := newTestAPI(t, Options{Compression: true}, testProposals()...)
+
+	for _, encoding := range []string{encodingGzip, encodingZstd} {
+		rec := serve(h, http.MethodGet, "/metrics", http.Header{"Accept-Encoding": {encoding}})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", encoding, rec.Code)
+		}
+
+		got := rec.Header().Get("Content-Encoding")
+		if got != encodingGzip && got != encodingZstd {
+			t.Fatalf("%s: Content-Encoding = %q", encoding, got)
+		}
+		if body := decode(t, got, rec.Body.Bytes()); !bytes.Contains(body, []byte("# HELP propmon_")) {
+			t.Errorf("%s: metrics are not readable after decoding once: %.100q", encoding, body)
+		}
+	}
+}
+
+func TestCompressUpgrade(t *testing.T) {
+	server := httptest.NewServer(newCompressEngine())
+	defer server.Close()
+
+	header := http.Header{"Accept-Encoding": {"gzip, zstd"}}
+	conn, res, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusSwitchingProtocols || res.Header.Get("Content-Encoding") != "" {
+		t.Errorf("upgrade response %d with Content-Encoding %q", res.StatusCode, res.Header.Get("Content-Encoding"))
+	}
+
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"upgraded":true}` {
+		t.Errorf("message = %q", data)
+	}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sch8ill/propmon/analytics"
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func TestConcentration(t *testing.T) {
+	repository := proposal.NewProposalRepository(time.Hour)
+	for _, p := range testProposals() {
+		repository.Store(p)
+	}
+
+	started := analytics.NewAnalyticsService(repository, time.Hour, 5)
+	started.Start()
+	t.Cleanup(started.Stop)
+
+	tests := []struct {
+		name    string
+		service *analytics.Service
+		status  int
+	}{
+		{"report", started, http.StatusOK},
+		{"no report yet", analytics.NewAnalyticsService(repository, time.Hour, 5), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, h := newTestAPI(t, Options{Analytics: tt.service})
+
+			rec := serve(h, http.MethodGet, "/api/v1/concentration", nil)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var report analytics.Report
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatal(err)
+			}
+			if report.Providers != 2 || report.Country.Groups != 2 {
+				t.Errorf("report = %+v, want two providers in two countries", report)
+			}
+			// the report is cached until the next one is computed
+			if cc := rec.Header().Get("Cache-Control"); !strings.HasPrefix(cc, "private, max-age=") || cc == "private, max-age=0" {
+				t.Errorf("Cache-Control = %q", cc)
+			}
+		})
+	}
+}
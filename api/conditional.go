@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// cacheRevalidate lets clients cache responses, but requires them to revalidate with the etag
+	cacheRevalidate = "private, no-cache"
+	cacheImmutable  = "public, max-age=3600"
+)
+
+// notModified sets the validators of the response and reports whether the client already has the
+// current representation, in which case the request is answered with 304 Not Modified.
+func notModified(c *gin.Context, etag string, modified time.Time, cacheControl string) bool {
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", cacheControl)
+
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+
+	match := false
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		match = etagMatches(inm, etag)
+	} else if ims := c.GetHeader("If-Modified-Since"); ims != "" {
+		if t, err := http.ParseTime(ims); err == nil {
+			match = !modified.Truncate(time.Second).After(t)
+		}
+	}
+
+	if match {
+		c.Status(http.StatusNotModified)
+	}
+	return match
+}
+
+// etagMatches compares the etags of an If-None-Match header weakly against etag.
+func etagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// generationETag identifies a representation derived from a repository generation.
+func generationETag(generation uint64) string {
+	return `W/"g` + strconv.FormatUint(generation, 10) + `"`
+}
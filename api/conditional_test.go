@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		etag   string
+		want   bool
+	}{
+		{`W/"g1"`, `W/"g1"`, true},
+		{`"g1"`, `W/"g1"`, true},
+		{`W/"g1"`, `"g1"`, true},
+		{`W/"g0", W/"g1"`, `W/"g1"`, true},
+		{`*`, `W/"g1"`, true},
+		{`W/"g2"`, `W/"g1"`, false},
+		{`W/"g10"`, `W/"g1"`, false},
+	}
+
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, tt.etag); got != tt.want {
+			t.Errorf("etagMatches(%q, %q) = %v, want %v", tt.header, tt.etag, got, tt.want)
+		}
+	}
+}
+
+func TestConditionalRequests(t *testing.T) {
+	a, h := newTestAPI(t, Options{}, testProposals()...)
+
+	paths := []string{
+		"/api/v1/proposals",
+		"/api/v1/export",
+		"/api/v1/providers.geojson",
+		"/api/v1/countries.geojson",
+		"/api/v4/proposals",
+		"/api/v1/openapi.json",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			first := serve(h, http.MethodGet, path, nil)
+			if first.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", first.Code)
+			}
+			etag := first.Header().Get("ETag")
+			modified := first.Header().Get("Last-Modified")
+			if etag == "" || modified == "" || first.Header().Get("Cache-Control") == "" {
+				t.Fatalf("missing validators: %v", first.Header())
+			}
+
+			tests := []struct {
+				name   string
+				header http.Header
+				status int
+			}{
+				{"matching etag", http.Header{"If-None-Match": {etag}}, http.StatusNotModified},
+				{"other etag", http.Header{"If-None-Match": {`W/"other"`}}, http.StatusOK},
+				{"not modified since", http.Header{"If-Modified-Since": {modified}}, http.StatusNotModified},
+				{"modified since", http.Header{"If-Modified-Since": {time.Unix(0, 0).UTC().Format(http.TimeFormat)}}, http.StatusOK},
+				// If-None-Match takes precedence over If-Modified-Since
+				{"both", http.Header{"If-None-Match": {`W/"other"`}, "If-Modified-Since": {modified}}, http.StatusOK},
+			}
+
+			for _, tt := range tests {
+				rec := serve(h, http.MethodGet, path, tt.header)
+				if rec.Code != tt.status {
+					t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.status)
+				}
+				if tt.status == http.StatusNotModified && rec.Body.Len() > 0 {
+					t.Errorf("%s: 304 response has a body", tt.name)
+				}
+				if rec.Header().Get("ETag") != etag {
+					t.Errorf("%s: etag = %q, want %q", tt.name, rec.Header().Get("ETag"), etag)
+				}
+			}
+		})
+	}
+
+	// changing the repository changes the etag of the proposal listings
+	before := serve(h, http.MethodGet, "/api/v1/proposals", nil).Header().Get("ETag")
+	a.repository.Store(&proposal.Proposal{ProviderID: "0xccc", ServiceType: "wireguard"})
+
+	rec := serve(h, http.MethodGet, "/api/v1/proposals", http.Header{"If-None-Match": {before}})
+	if rec.Code != http.StatusOK {
+		t.Errorf("status after a change = %d, want 200", rec.Code)
+	}
+	if rec.Header().Get("ETag") == before {
+		t.Error("etag did not change with the repository")
+	}
+}
+
+func TestConditionalStats(t *testing.T) {
+	_, h := newTestAPI(t, Options{}, testProposals()...)
+
+	first := serve(h, http.MethodGet, "/api/v1/stats", nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", first.Code)
+	}
+
+	rec := serve(h, http.MethodGet, "/api/v1/stats", http.Header{"If-None-Match": {first.Header().Get("ETag")}})
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want 304 while the stats are cached", rec.Code)
+	}
+	if cc := first.Header().Get("Cache-Control"); cc != "private, max-age=60" && cc != "private, max-age=59" {
+		t.Errorf("cache control = %q, want the remaining cache ttl", cc)
+	}
+}
+
+func TestConditionalAfterRestart(t *testing.T) {
+	_, before := newTestAPI(t, Options{}, testProposals()...)
+	etag := serve(before, http.MethodGet, "/api/v1/proposals", nil).Header().Get("ETag")
+
+	// a restarted instance with different contents must not accept the etag of the previous one
+	time.Sleep(time.Millisecond)
+	_, after := newTestAPI(t, Options{}, testProposals()[:2]...)
+
+	if rec := serve(after, http.MethodGet, "/api/v1/proposals", http.Header{"If-None-Match": {etag}}); rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 for an etag of a previous process", rec.Code)
+	}
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+// discoveryAllAccessPolicies disables access policy filtering on the discovery endpoint
+const discoveryAllAccessPolicies = "all"
+
+// getDiscoveryProposals mirrors the /api/v4/proposals endpoint of the mysterium discovery service.
+func (h *handler) getDiscoveryProposals(c *gin.Context) {
+	filter, err := discoveryFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	generation := h.repository.Generation()
+	if notModified(c, generationETag(generation.ID), generation.Modified, cacheRevalidate) {
+		return
+	}
+
+	proposals, _, err := h.repository.Query(proposal.Query{Filter: filter})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if proposals == nil {
+		proposals = []*proposal.Proposal{}
+	}
+	c.JSON(http.StatusOK, proposals)
+}
+
+func discoveryFilter(c *gin.Context) (*proposal.Filter, error) {
+	filter := proposal.NewFilter()
+
+	params := []struct {
+		name  string
+		field proposal.Field
+		op    proposal.Operator
+	}{
+		{"provider_id", proposal.FieldProviderID, proposal.OpEqual},
+		{"service_type", proposal.FieldServiceType, proposal.OpEqual},
+		{"location_country", proposal.FieldCountry, proposal.OpEqual},
+		{"ip_type", proposal.FieldIpType, proposal.OpEqual},
+		{"access_policy_source", proposal.FieldAccessPolicySource, proposal.OpEqual},
+		{"compatibility_min", proposal.FieldCompatibility, proposal.OpMin},
+		{"compatibility_max", proposal.FieldCompatibility, proposal.OpMax},
+		{"quality_min", proposal.FieldQuality, proposal.OpMin},
+	}
+
+	for _, param := range params {
+		values := c.QueryArray(param.name)
+		if len(values) == 0 || values[0] == "" {
+			continue
+		}
+		if err := filter.Where(param.field, param.op, values...); err != nil {
+			return nil, err
+		}
+	}
+
+	// like discovery, only proposals without access policies are listed unless a policy is requested
+	switch accessPolicy := c.Query("access_policy"); accessPolicy {
+	case discoveryAllAccessPolicies:
+	case "":
+		if c.Query("access_policy_source") == "" {
+			if err := filter.Where(proposal.FieldHasAccessPolicy, proposal.OpEqual, "false"); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		if err := filter.Where(proposal.FieldAccessPolicyID, proposal.OpEqual, accessPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	return filter, nil
+}
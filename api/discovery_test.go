@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func TestDiscoveryProposals(t *testing.T) {
+	_, h := newTestAPI(t, Options{}, testProposals()...)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"without access policies by default", "", []string{"0xaaa.openvpn", "0xaaa.wireguard"}},
+		{"all access policies", "?access_policy=all", []string{"0xaaa.openvpn", "0xaaa.wireguard", "0xbbb.wireguard"}},
+		{"access policy", "?access_policy=mysterium", []string{"0xbbb.wireguard"}},
+		{"access policy source", "?access_policy_source=https://trust.mysterium.network/api/v1/access-policies/mysterium", []string{"0xbbb.wireguard"}},
+		{"service type", "?service_type=wireguard", []string{"0xaaa.wireguard"}},
+		{"country", "?location_country=DE&access_policy=all", []string{"0xaaa.openvpn", "0xaaa.wireguard"}},
+		{"ip type", "?ip_type=hosting&access_policy=all", []string{"0xbbb.wireguard"}},
+		{"compatibility", "?compatibility_min=2", []string{"0xaaa.wireguard"}},
+		{"quality", "?quality_min=2", []string{"0xaaa.wireguard"}},
+		{"repeated parameter", "?provider_id=0xaaa&provider_id=0xbbb&access_policy=all", []string{"0xaaa.openvpn", "0xaaa.wireguard", "0xbbb.wireguard"}},
+		{"no match", "?location_country=FR", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(h, http.MethodGet, "/api/v4/proposals"+tt.query, nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+
+			var proposals []*proposal.Proposal
+			if err := json.Unmarshal(rec.Body.Bytes(), &proposals); err != nil {
+				t.Fatal(err)
+			}
+			if proposals == nil {
+				t.Fatal("response is not a json array")
+			}
+
+			got := make([]string, len(proposals))
+			for i, p := range proposals {
+				got[i] = p.ServiceKey()
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("proposals = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoveryInvalidFilter(t *testing.T) {
+	_, h := newTestAPI(t, Options{})
+
+	if rec := serve(h, http.MethodGet, "/api/v4/proposals?quality_min=high", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
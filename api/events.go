@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+const (
+	eventKeepAliveInterval = 15 * time.Second
+	websocketWriteTimeout  = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// getEvents streams repository events as server-sent events, or over a websocket if the
+// client requests an upgrade. Clients resume after a reconnect by passing the ID of the last
+// event they received as Last-Event-ID header or last_event_id query parameter.
+func (h *handler) getEvents(c *gin.Context) {
+	filter, err := proposal.ParseFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lastID, err := lastEventID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		h.streamWebsocket(c, filter, lastID)
+		return
+	}
+	h.streamSSE(c, filter, lastID)
+}
+
+func lastEventID(c *gin.Context) (uint64, error) {
+	id := c.GetHeader("Last-Event-ID")
+	if id == "" {
+		id = c.Query("last_event_id")
+	}
+	if id == "" {
+		return 0, nil
+	}
+
+	lastID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid last event id %q", id)
+	}
+
+	return lastID, nil
+}
+
+func (h *handler) streamSSE(c *gin.Context, filter *proposal.Filter, lastID uint64) {
+	sub := h.repository.Subscribe(filter, lastID, h.eventBufferSize)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+
+		case <-keepAlive.C:
+			if _, err := c.Writer.WriteString(": keep-alive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+
+		case e, ok := <-sub.C:
+			if !ok {
+				log.Debug().Str("client", c.ClientIP()).Msg("Disconnecting slow event stream client")
+				return
+			}
+
+			data, err := json.Marshal(e)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+func (h *handler) streamWebsocket(c *gin.Context, filter *proposal.Filter, lastID uint64) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	sub := h.repository.Subscribe(filter, lastID, h.eventBufferSize)
+	defer sub.Close()
+
+	// the read loop only exists to notice when the client goes away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	keepAlive := time.NewTicker(eventKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+
+		case <-keepAlive.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketWriteTimeout)); err != nil {
+				return
+			}
+
+		case e, ok := <-sub.C:
+			if !ok {
+				log.Debug().Str("client", c.ClientIP()).Msg("Disconnecting slow event stream client")
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "event buffer overflow"),
+					time.Now().Add(websocketWriteTimeout))
+				return
+			}
+
+			_ = conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+			if err := conn.WriteJSON(e); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+type sseFrame struct {
+	id    uint64
+	event string
+	data  proposal.Event
+}
+
+// readSSE reads the next event of a server-sent event stream, skipping comments.
+func readSSE(t *testing.T, r *bufio.Reader) (sseFrame, error) {
+	t.Helper()
+
+	var f sseFrame
+	var fields int
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return f, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "" && fields > 0:
+			return f, nil
+		case line == "" || strings.HasPrefix(line, ":"):
+		case strings.HasPrefix(line, "id: "):
+			if f.id, err = strconv.ParseUint(strings.TrimPrefix(line, "id: "), 10, 64); err != nil {
+				t.Fatalf("invalid id line %q", line)
+			}
+			fields++
+		case strings.HasPrefix(line, "event: "):
+			f.event = strings.TrimPrefix(line, "event: ")
+			fields++
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &f.data); err != nil {
+				t.Fatalf("invalid data line %q: %v", line, err)
+			}
+			fields++
+		default:
+			t.Fatalf("unexpected line %q", line)
+		}
+	}
+}
+
+func newEventServer(t *testing.T, bufferSize int) (*proposal.Repository, *httptest.Server) {
+	t.Helper()
+
+	a, h := newTestAPI(t, Options{EventBufferSize: bufferSize})
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+	return a.repository, server
+}
+
+func openSSE(t *testing.T, server *httptest.Server, query string, header http.Header) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/events"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { res.Body.Close() })
+	return res
+}
+
+func TestEventsSSE(t *testing.T) {
+	repository, server := newEventServer(t, 16)
+
+	wireguard := &proposal.Proposal{ProviderID: "0xaaa", ServiceType: "wireguard"}
+	openvpn := &proposal.Proposal{ProviderID: "0xaaa", ServiceType: "openvpn"}
+
+	lastID := repository.DebugStats().LastEventID
+	repository.Store(wireguard)
+	repository.Store(openvpn)
+
+	tests := []struct {
+		name   string
+		query  string
+		header http.Header
+		want   []string
+	}{
+		{"live only", "", nil, []string{"unregistered/wireguard"}},
+		{"replay from header", "", http.Header{"Last-Event-Id": {strconv.FormatUint(lastID, 10)}},
+			[]string{"registered/wireguard", "registered/openvpn", "unregistered/wireguard"}},
+		{"replay from query", "?last_event_id=" + strconv.FormatUint(lastID+1, 10), nil,
+			[]string{"registered/openvpn", "unregistered/wireguard"}},
+		{"filtered replay", "?service=openvpn&last_event_id=" + strconv.FormatUint(lastID, 10), nil,
+			[]string{"registered/openvpn"}},
+	}
+
+	var streams []*bufio.Reader
+	for _, tt := range tests {
+		res := openSSE(t, server, tt.query, tt.header)
+		if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("%s: status %d, content type %q", tt.name, res.StatusCode, res.Header.Get("Content-Type"))
+		}
+		streams = append(streams, bufio.NewReader(res.Body))
+	}
+
+	// the live event is published after every stream is subscribed
+	repository.Remove(wireguard.ServiceKey())
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var previous uint64
+			for _, want := range tt.want {
+				f, err := readSSE(t, streams[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := f.event + "/" + f.data.Proposal.ServiceType; got != want {
+					t.Errorf("event = %s, want %s", got, want)
+				}
+				if f.id != f.data.ID || string(f.data.Type) != f.event || f.id <= previous {
+					t.Errorf("frame id %d and event %s do not match data %+v", f.id, f.event, f.data)
+				}
+				previous = f.id
+			}
+		})
+	}
+}
+
+func TestEventsInvalidLastEventID(t *testing.T) {
+	_, h := newTestAPI(t, Options{EventBufferSize: 16})
+
+	for _, header := range []http.Header{{"Last-Event-Id": {"abc"}}, {"Last-Event-Id": {"-1"}}} {
+		if rec := serve(h, http.MethodGet, "/api/v1/events", header); rec.Code != http.StatusBadRequest {
+			t.Errorf("%v: status = %d, want 400", header, rec.Code)
+		}
+	}
+}
+
+func dialEvents(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+
+	conn, res, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/v1/events"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestEventsWebsocket(t *testing.T) {
+	repository, server := newEventServer(t, 16)
+
+	p := &proposal.Proposal{ProviderID: "0xaaa", ServiceType: "wireguard"}
+	lastID := repository.DebugStats().LastEventID
+	repository.Store(p)
+
+	conn := dialEvents(t, server, "?last_event_id="+strconv.FormatUint(lastID, 10))
+	repository.Remove(p.ServiceKey())
+
+	for _, want := range []proposal.EventType{proposal.EventRegistered, proposal.EventUnregistered} {
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		var e proposal.Event
+		if err := conn.ReadJSON(&e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Type != want || e.Proposal == nil || e.Proposal.ProviderID != "0xaaa" {
+			t.Errorf("event = %+v, want %s of 0xaaa", e, want)
+		}
+	}
+}
+
+// largeProposals returns proposals that fill the socket buffers quickly, so a client that does not read
+// falls behind the published events.
+func largeProposals(n int) []*proposal.Proposal {
+	isp := strings.Repeat("x", 1<<20)
+	proposals := make([]*proposal.Proposal, n)
+	for i := range proposals {
+		proposals[i] = &proposal.Proposal{ProviderID: "0x" + strconv.Itoa(i), ServiceType: "wireguard", Location: proposal.Location{Isp: isp}}
+	}
+	return proposals
+}
+
+func TestEventsOverflow(t *testing.T) {
+	const published = 64
+
+	t.Run("sse", func(t *testing.T) {
+		repository, server := newEventServer(t, 1)
+		res := openSSE(t, server, "", nil)
+
+		for _, p := range largeProposals(published) {
+			repository.Store(p)
+		}
+
+		// the stream of a client that fell behind ends before all events were sent
+		r := bufio.NewReader(res.Body)
+		var received int
+		for {
+			if _, err := readSSE(t, r); err != nil {
+				if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+					t.Fatal(err)
+				}
+				break
+			}
+			received++
+		}
+		if received >= published {
+			t.Errorf("received all %d events, want the stream to end on overflow", received)
+		}
+	})
+
+	t.Run("websocket", func(t *testing.T) {
+		repository, server := newEventServer(t, 1)
+		conn := dialEvents(t, server, "")
+
+		for _, p := range largeProposals(published) {
+			repository.Store(p)
+		}
+
+		var received int
+		for {
+			_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+			if _, _, err := conn.ReadMessage(); err != nil {
+				var closeErr *websocket.CloseError
+				if !errors.As(err, &closeErr) {
+					t.Fatalf("connection ended without a close message: %v", err)
+				}
+				if closeErr.Code != websocket.ClosePolicyViolation || closeErr.Text != "event buffer overflow" {
+					t.Errorf("close = %d %q, want %d %q", closeErr.Code, closeErr.Text, websocket.ClosePolicyViolation, "event buffer overflow")
+				}
+				break
+			}
+			received++
+		}
+		if received >= published {
+			t.Errorf("received all %d events, want a close on overflow", received)
+		}
+	})
+}
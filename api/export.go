@@ -0,0 +1,57 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/sch8ill/propmon/export"
+	"github.com/sch8ill/propmon/proposal"
+)
+
+// getExport streams every proposal matching the filters in the requested format.
+func (h *handler) getExport(c *gin.Context) {
+	format, err := export.ParseFormat(c.DefaultQuery("format", string(export.FormatNDJSON)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := proposal.ParseFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	generation := h.repository.Generation()
+	if notModified(c, generationETag(generation.ID), generation.Modified, cacheRevalidate) {
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"propmon-%s.%s\"",
+		time.Now().UTC().Format("20060102T150405Z"), format))
+	c.Status(http.StatusOK)
+
+	writer, err := export.NewWriter(format, c.Writer)
+	if err != nil {
+		return
+	}
+
+	for _, p := range h.repository.Proposals() {
+		if !filter.Match(p) {
+			continue
+		}
+		if err := writer.Write(p); err != nil {
+			log.Debug().Err(err).Msg("Failed to write export")
+			return
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Debug().Err(err).Msg("Failed to write export")
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"bufio"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	_, h := newTestAPI(t, Options{}, testProposals()...)
+
+	tests := []struct {
+		query       string
+		status      int
+		contentType string
+		lines       int
+	}{
+		{"", http.StatusOK, "application/x-ndjson", 3},
+		{"?format=ndjson&country=DE", http.StatusOK, "application/x-ndjson", 2},
+		{"?format=csv&service=openvpn", http.StatusOK, "text/csv", 2},
+		{"?format=csv&country=FR", http.StatusOK, "text/csv", 1},
+		{"?format=xml", http.StatusBadRequest, "application/json; charset=utf-8", 1},
+		{"?asn=abc", http.StatusBadRequest, "application/json; charset=utf-8", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := serve(h, http.MethodGet, "/api/v1/export"+tt.query, nil)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("content type = %q, want %q", got, tt.contentType)
+			}
+			if tt.status == http.StatusOK && !strings.HasPrefix(rec.Header().Get("Content-Disposition"), "attachment; filename=\"propmon-") {
+				t.Errorf("content disposition = %q", rec.Header().Get("Content-Disposition"))
+			}
+
+			lines := 0
+			scanner := bufio.NewScanner(rec.Body)
+			for scanner.Scan() {
+				lines++
+			}
+			if lines != tt.lines {
+				t.Errorf("%d lines, want %d", lines, tt.lines)
+			}
+		})
+	}
+}
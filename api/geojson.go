@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sch8ill/propmon/geo"
+	"github.com/sch8ill/propmon/proposal"
+)
+
+const geoJSONContentType = "application/geo+json"
+
+// getProvidersGeoJSON returns the providers matching the filters as point features at the centroid
+// of their region or country.
+func (h *handler) getProvidersGeoJSON(c *gin.Context) {
+	h.serveGeoJSON(c, h.locator.Providers)
+}
+
+// getCountriesGeoJSON returns the number of providers and their average quality per country.
+func (h *handler) getCountriesGeoJSON(c *gin.Context) {
+	h.serveGeoJSON(c, h.locator.Countries)
+}
+
+func (h *handler) serveGeoJSON(c *gin.Context, features func([]*proposal.Provider) *geo.FeatureCollection) {
+	filter, err := proposal.ParseFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	generation := h.repository.Generation()
+	if notModified(c, generationETag(generation.ID), generation.Modified, cacheRevalidate) {
+		return
+	}
+
+	data, err := json.Marshal(features(h.repository.MatchingProviders(filter)))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, geoJSONContentType, data)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sch8ill/propmon/geo"
+)
+
+func TestGeoJSON(t *testing.T) {
+	_, h := newTestAPI(t, Options{}, testProposals()...)
+
+	tests := []struct {
+		target   string
+		features int
+	}{
+		{"/api/v1/providers.geojson", 2},
+		{"/api/v1/providers.geojson?country=US", 1},
+		{"/api/v1/providers.geojson?country=FR", 0},
+		{"/api/v1/countries.geojson", 2},
+		{"/api/v1/countries.geojson?service=openvpn", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := serve(h, http.MethodGet, tt.target, nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != geoJSONContentType {
+				t.Errorf("content type = %q, want %q", ct, geoJSONContentType)
+			}
+
+			var fc geo.FeatureCollection
+			if err := json.Unmarshal(rec.Body.Bytes(), &fc); err != nil {
+				t.Fatal(err)
+			}
+			if fc.Features == nil || len(fc.Features) != tt.features {
+				t.Errorf("got %d features, want %d", len(fc.Features), tt.features)
+			}
+		})
+	}
+}
+
+func TestGeoJSONInvalidFilter(t *testing.T) {
+	_, h := newTestAPI(t, Options{})
+
+	for _, target := range []string{"/api/v1/providers.geojson?asn=abc", "/api/v1/countries.geojson?quality_min=high"} {
+		if rec := serve(h, http.MethodGet, target, nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", target, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGeoJSONCentroids(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "centroids.csv")
+	if err := os.WriteFile(path, []byte("country,region,latitude,longitude\nUS,,1,2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, h := newTestAPI(t, Options{GeoCentroids: path}, testProposals()...)
+
+	rec := serve(h, http.MethodGet, "/api/v1/providers.geojson?country=US", nil)
+	var fc geo.FeatureCollection
+	if err := json.Unmarshal(rec.Body.Bytes(), &fc); err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Features) != 1 || fc.Features[0].Geometry.Coordinates != [2]float64{2, 1} {
+		t.Errorf("features = %+v, want a single feature at the loaded centroid", fc.Features)
+	}
+
+	a := New(nil, nil, nil, nil, Options{GeoCentroids: filepath.Join(t.TempDir(), "missing.csv")})
+	if err := a.register(); err == nil {
+		t.Error("register succeeded with missing centroids, want an error")
+	}
+}
@@ -0,0 +1,517 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+const (
+	graphQLMaxDepth       = 8
+	graphQLMaxQueryLength = 8192
+	graphQLMaxParallelism = 8
+	// graphQLMaxComplexity is the maximum number of values a single query may resolve,
+	// estimated from the list limits and the selected fields
+	graphQLMaxComplexity = 50000
+)
+
+//go:embed schema.graphql
+var graphQLSchema string
+
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+func newGraphQLSchema(repository *proposal.Repository) *graphql.Schema {
+	return graphql.MustParseSchema(graphQLSchema, &queryResolver{repository: repository},
+		graphql.UseFieldResolvers(),
+		graphql.MaxDepth(graphQLMaxDepth),
+		graphql.MaxQueryLength(graphQLMaxQueryLength),
+		graphql.MaxParallelism(graphQLMaxParallelism),
+	)
+}
+
+// serveGraphQL executes queries sent as json body of a POST request or as query parameters of a GET request.
+func serveGraphQL(schema *graphql.Schema) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req graphQLRequest
+		if c.Request.Method == http.MethodGet {
+			req.Query = c.Query("query")
+			req.OperationName = c.Query("operationName")
+			if variables := c.Query("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid variables"})
+					return
+				}
+			}
+		} else if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid graphql request"})
+			return
+		}
+
+		if req.Query == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing query"})
+			return
+		}
+
+		ctx := context.WithValue(c.Request.Context(), complexityContextKey{}, &complexity{})
+		c.JSON(http.StatusOK, schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+	}
+}
+
+type complexityContextKey struct{}
+
+// complexity tracks the estimated cost of a query across its top level fields.
+type complexity struct {
+	cost int
+	mu   sync.Mutex
+}
+
+var errQueryTooComplex = errors.New("query exceeds the maximum complexity of " + strconv.Itoa(graphQLMaxComplexity))
+
+// charge adds the cost of resolving a list of n objects with the selected fields.
+func charge(ctx context.Context, n int) error {
+	c, ok := ctx.Value(complexityContextKey{}).(*complexity)
+	if !ok {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cost += n * (1 + len(graphql.SelectedFieldNames(ctx)))
+	if c.cost > graphQLMaxComplexity {
+		return errQueryTooComplex
+	}
+	return nil
+}
+
+type queryResolver struct {
+	repository *proposal.Repository
+}
+
+type listArgs struct {
+	Filter *proposalFilter
+	Sort   *string
+	Limit  int32
+}
+
+func (a listArgs) query() (proposal.Query, error) {
+	q := proposal.Query{Limit: int(a.Limit)}
+	if q.Limit <= 0 || q.Limit > maxResponseCount {
+		q.Limit = maxResponseCount
+	}
+
+	if a.Sort != nil {
+		sort, err := proposal.ParseSort(*a.Sort)
+		if err != nil {
+			return q, err
+		}
+		q.Sort = sort
+	}
+
+	filter, err := a.Filter.filter()
+	if err != nil {
+		return q, err
+	}
+	q.Filter = filter
+
+	return q, nil
+}
+
+func (r *queryResolver) Providers(ctx context.Context, args listArgs) ([]*providerResolver, error) {
+	q, err := args.query()
+	if err != nil {
+		return nil, err
+	}
+	if err := charge(ctx, q.Limit); err != nil {
+		return nil, err
+	}
+
+	limit := q.Limit
+	q.Limit = 0
+	proposals, _, err := r.repository.Query(q)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	seen := make(map[string]struct{})
+	for _, p := range proposals {
+		if len(ids) == limit {
+			break
+		}
+		if _, ok := seen[p.ProviderID]; ok {
+			continue
+		}
+		seen[p.ProviderID] = struct{}{}
+		ids = append(ids, p.ProviderID)
+	}
+
+	providers, err := r.providers(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*providerResolver, 0, len(ids))
+	for _, id := range ids {
+		resolvers = append(resolvers, providers[id])
+	}
+	return resolvers, nil
+}
+
+func (r *queryResolver) Provider(ctx context.Context, args struct{ ID string }) (*providerResolver, error) {
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	providers, err := r.providers([]string{args.ID})
+	if err != nil {
+		return nil, err
+	}
+	return providers[args.ID], nil
+}
+
+// providers groups all proposals of the providers.
+func (r *queryResolver) providers(ids []string) (map[string]*providerResolver, error) {
+	providers := make(map[string]*providerResolver, len(ids))
+	if len(ids) == 0 {
+		return providers, nil
+	}
+
+	filter := proposal.NewFilter()
+	if err := filter.Where(proposal.FieldProviderID, proposal.OpEqual, ids...); err != nil {
+		return nil, err
+	}
+
+	proposals, _, err := r.repository.Query(proposal.Query{Filter: filter})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range proposals {
+		provider, ok := providers[p.ProviderID]
+		if !ok {
+			provider = &providerResolver{proposal: p}
+			providers[p.ProviderID] = provider
+		}
+		provider.services = append(provider.services, p)
+	}
+
+	return providers, nil
+}
+
+type proposalPage struct {
+	Proposals []*proposalResolver
+	Next      *string
+}
+
+func (r *queryResolver) Proposals(ctx context.Context, args struct {
+	listArgs
+	After *string
+}) (*proposalPage, error) {
+	q, err := args.query()
+	if err != nil {
+		return nil, err
+	}
+	if args.After != nil {
+		q.Cursor = *args.After
+	}
+	if err := charge(ctx, q.Limit); err != nil {
+		return nil, err
+	}
+
+	proposals, next, err := r.repository.Query(q)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &proposalPage{Proposals: make([]*proposalResolver, 0, len(proposals))}
+	for _, p := range proposals {
+		page.Proposals = append(page.Proposals, &proposalResolver{p})
+	}
+	if next != "" {
+		page.Next = &next
+	}
+
+	return page, nil
+}
+
+func (r *queryResolver) Proposal(ctx context.Context, args struct{ Key string }) (*proposalResolver, error) {
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	p := r.repository.Get(args.Key)
+	if p == nil {
+		return nil, nil
+	}
+	return &proposalResolver{p}, nil
+}
+
+type proposalFilter struct {
+	ProviderID         *[]string
+	ServiceType        *[]string
+	ServiceTypeNot     *[]string
+	Continent          *[]string
+	Country            *[]string
+	CountryNot         *[]string
+	Region             *[]string
+	City               *[]string
+	Asn                *[]int32
+	AsnNot             *[]int32
+	Isp                *[]string
+	IpType             *[]string
+	AccessPolicy       *[]string
+	AccessPolicySource *[]string
+	HasAccessPolicy    *bool
+	HasQuality         *bool
+	CompatibilityMin   *int32
+	CompatibilityMax   *int32
+	QualityMin         *float64
+	QualityMax         *float64
+	LatencyMin         *float64
+	LatencyMax         *float64
+	BandwidthMin       *float64
+	BandwidthMax       *float64
+	UptimeMin          *float64
+	UptimeMax          *float64
+}
+
+func (f *proposalFilter) filter() (*proposal.Filter, error) {
+	filter := proposal.NewFilter()
+	if f == nil {
+		return filter, nil
+	}
+
+	strs := []struct {
+		field  proposal.Field
+		op     proposal.Operator
+		values *[]string
+	}{
+		{proposal.FieldProviderID, proposal.OpEqual, f.ProviderID},
+		{proposal.FieldServiceType, proposal.OpEqual, f.ServiceType},
+		{proposal.FieldServiceType, proposal.OpNotEqual, f.ServiceTypeNot},
+		{proposal.FieldContinent, proposal.OpEqual, f.Continent},
+		{proposal.FieldCountry, proposal.OpEqual, f.Country},
+		{proposal.FieldCountry, proposal.OpNotEqual, f.CountryNot},
+		{proposal.FieldRegion, proposal.OpEqual, f.Region},
+		{proposal.FieldCity, proposal.OpEqual, f.City},
+		{proposal.FieldAsn, proposal.OpEqual, ints(f.Asn)},
+		{proposal.FieldAsn, proposal.OpNotEqual, ints(f.AsnNot)},
+		{proposal.FieldIsp, proposal.OpEqual, f.Isp},
+		{proposal.FieldIpType, proposal.OpEqual, f.IpType},
+		{proposal.FieldAccessPolicyID, proposal.OpEqual, f.AccessPolicy},
+		{proposal.FieldAccessPolicySource, proposal.OpEqual, f.AccessPolicySource},
+	}
+	for _, s := range strs {
+		if s.values == nil || len(*s.values) == 0 {
+			continue
+		}
+		if err := filter.Where(s.field, s.op, *s.values...); err != nil {
+			return nil, err
+		}
+	}
+
+	bools := []struct {
+		field proposal.Field
+		value *bool
+	}{
+		{proposal.FieldHasAccessPolicy, f.HasAccessPolicy},
+		{proposal.FieldHasQuality, f.HasQuality},
+	}
+	for _, b := range bools {
+		if b.value == nil {
+			continue
+		}
+		if err := filter.Where(b.field, proposal.OpEqual, strconv.FormatBool(*b.value)); err != nil {
+			return nil, err
+		}
+	}
+
+	nums := []struct {
+		field proposal.Field
+		op    proposal.Operator
+		value *float64
+	}{
+		{proposal.FieldCompatibility, proposal.OpMin, float(f.CompatibilityMin)},
+		{proposal.FieldCompatibility, proposal.OpMax, float(f.CompatibilityMax)},
+		{proposal.FieldQuality, proposal.OpMin, f.QualityMin},
+		{proposal.FieldQuality, proposal.OpMax, f.QualityMax},
+		{proposal.FieldLatency, proposal.OpMin, f.LatencyMin},
+		{proposal.FieldLatency, proposal.OpMax, f.LatencyMax},
+		{proposal.FieldBandwidth, proposal.OpMin, f.BandwidthMin},
+		{proposal.FieldBandwidth, proposal.OpMax, f.BandwidthMax},
+		{proposal.FieldUptime, proposal.OpMin, f.UptimeMin},
+		{proposal.FieldUptime, proposal.OpMax, f.UptimeMax},
+	}
+	for _, n := range nums {
+		if n.value == nil {
+			continue
+		}
+		if err := filter.Where(n.field, n.op, strconv.FormatFloat(*n.value, 'f', -1, 64)); err != nil {
+			return nil, err
+		}
+	}
+
+	return filter, nil
+}
+
+func ints(values *[]int32) *[]string {
+	if values == nil {
+		return nil
+	}
+
+	strs := make([]string, 0, len(*values))
+	for _, v := range *values {
+		strs = append(strs, strconv.Itoa(int(v)))
+	}
+	return &strs
+}
+
+func float(value *int32) *float64 {
+	if value == nil {
+		return nil
+	}
+
+	f := float64(*value)
+	return &f
+}
+
+// providerResolver resolves a provider from its proposals, the location and quality are taken from the first one.
+type providerResolver struct {
+	proposal *proposal.Proposal
+	services []*proposal.Proposal
+}
+
+func (r *providerResolver) ID() string {
+	return r.proposal.ProviderID
+}
+
+func (r *providerResolver) Location() *locationResolver {
+	return &locationResolver{r.proposal.Location}
+}
+
+func (r *providerResolver) Quality() *proposal.Quality {
+	return r.proposal.Quality
+}
+
+func (r *providerResolver) Services(args struct{ ServiceType *string }) []*serviceResolver {
+	services := make([]*serviceResolver, 0, len(r.services))
+	for _, p := range r.services {
+		if args.ServiceType != nil && p.ServiceType != *args.ServiceType {
+			continue
+		}
+		services = append(services, &serviceResolver{p})
+	}
+	return services
+}
+
+type serviceResolver struct {
+	proposal *proposal.Proposal
+}
+
+func (r *serviceResolver) ServiceType() string {
+	return r.proposal.ServiceType
+}
+
+func (r *serviceResolver) Compatibility() int32 {
+	return int32(r.proposal.Compatibility)
+}
+
+func (r *serviceResolver) Contacts() []*contactResolver {
+	return contacts(r.proposal)
+}
+
+func (r *serviceResolver) AccessPolicies() []proposal.AccessPolicy {
+	return accessPolicies(r.proposal)
+}
+
+type proposalResolver struct {
+	proposal *proposal.Proposal
+}
+
+func (r *proposalResolver) Key() string {
+	return r.proposal.ServiceKey()
+}
+
+func (r *proposalResolver) Format() string {
+	return r.proposal.Format
+}
+
+func (r *proposalResolver) Compatibility() int32 {
+	return int32(r.proposal.Compatibility)
+}
+
+func (r *proposalResolver) ProviderID() string {
+	return r.proposal.ProviderID
+}
+
+func (r *proposalResolver) ServiceType() string {
+	return r.proposal.ServiceType
+}
+
+func (r *proposalResolver) Location() *locationResolver {
+	return &locationResolver{r.proposal.Location}
+}
+
+func (r *proposalResolver) Contacts() []*contactResolver {
+	return contacts(r.proposal)
+}
+
+func (r *proposalResolver) Quality() *proposal.Quality {
+	return r.proposal.Quality
+}
+
+func (r *proposalResolver) AccessPolicies() []proposal.AccessPolicy {
+	return accessPolicies(r.proposal)
+}
+
+type locationResolver struct {
+	proposal.Location
+}
+
+func (r *locationResolver) Asn() int32 {
+	return int32(r.Location.Asn)
+}
+
+type contactResolver struct {
+	contact proposal.Contact
+}
+
+func (r *contactResolver) Type() string {
+	return r.contact.Type
+}
+
+func (r *contactResolver) BrokerAddresses() []string {
+	if r.contact.Definition.BrokerAddresses == nil {
+		return []string{}
+	}
+	return r.contact.Definition.BrokerAddresses
+}
+
+func contacts(p *proposal.Proposal) []*contactResolver {
+	contacts := make([]*contactResolver, 0, len(p.Contacts))
+	for _, c := range p.Contacts {
+		contacts = append(contacts, &contactResolver{c})
+	}
+	return contacts
+}
+
+func accessPolicies(p *proposal.Proposal) []proposal.AccessPolicy {
+	if p.AccessPolicies == nil {
+		return []proposal.AccessPolicy{}
+	}
+	return p.AccessPolicies
+}
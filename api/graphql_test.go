@@ -0,0 +1,207 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type graphQLResult struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func postGraphQL(t *testing.T, h http.Handler, query string, variables map[string]any) graphQLResult {
+	t.Helper()
+
+	body, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	var res graphQLResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestGraphQLQueries(t *testing.T) {
+	_, h := newTestAPI(t, Options{}, testProposals()...)
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		want      string
+	}{
+		{
+			"providers",
+			`{providers{id}}`, nil,
+			`{"providers":[{"id":"0xaaa"},{"id":"0xbbb"}]}`,
+		},
+		{
+			"providers filtered and sorted",
+			`{providers(filter:{hasQuality:true},sort:"-quality"){id services{serviceType}}}`, nil,
+			`{"providers":[{"id":"0xaaa","services":[{"serviceType":"openvpn"},{"serviceType":"wireguard"}]}]}`,
+		},
+		{
+			"provider services",
+			`{provider(id:"0xaaa"){location{country asn} services(serviceType:"openvpn"){serviceType compatibility}}}`, nil,
+			`{"provider":{"location":{"country":"DE","asn":3320},"services":[{"serviceType":"openvpn","compatibility":1}]}}`,
+		},
+		{
+			"unknown provider",
+			`{provider(id:"0xccc"){id}}`, nil,
+			`{"provider":null}`,
+		},
+		{
+			"proposals with variables",
+			`query($f: ProposalFilter){proposals(filter:$f){proposals{key} next}}`,
+			map[string]any{"f": map[string]any{"asnNot": []int{3320}}},
+			`{"proposals":{"proposals":[{"key":"0xbbb.wireguard"}],"next":null}}`,
+		},
+		{
+			"proposal",
+			`{proposal(key:"0xbbb.wireguard"){providerId accessPolicies{id} contacts{type}}}`, nil,
+			`{"proposal":{"providerId":"0xbbb","accessPolicies":[{"id":"mysterium"}],"contacts":[]}}`,
+		},
+		{
+			"compatibility range",
+			`{proposals(filter:{compatibilityMin:2,countryNot:["US"]}){proposals{key}}}`, nil,
+			`{"proposals":{"proposals":[{"key":"0xaaa.wireguard"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := postGraphQL(t, h, tt.query, tt.variables)
+			if len(res.Errors) > 0 {
+				t.Fatalf("errors: %+v", res.Errors)
+			}
+
+			var got, want any
+			if err := json.Unmarshal(res.Data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if gotJSON, _ := json.Marshal(got); string(gotJSON) != mustMarshal(want) {
+				t.Errorf("data = %s, want %s", gotJSON, tt.want)
+			}
+		})
+	}
+}
+
+func mustMarshal(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func TestGraphQLPagination(t *testing.T) {
+	_, h := newTestAPI(t, Options{}, testProposals()...)
+
+	var keys []string
+	var after *string
+	for range 5 {
+		res := postGraphQL(t, h, `query($after: String){proposals(limit:2, after:$after){proposals{key} next}}`,
+			map[string]any{"after": after})
+		if len(res.Errors) > 0 {
+			t.Fatalf("errors: %+v", res.Errors)
+		}
+
+		var data struct {
+			Proposals struct {
+				Proposals []struct{ Key string }
+				Next      *string
+			}
+		}
+		if err := json.Unmarshal(res.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range data.Proposals.Proposals {
+			keys = append(keys, p.Key)
+		}
+		if after = data.Proposals.Next; after == nil {
+			break
+		}
+	}
+
+	if got := strings.Join(keys, ","); got != "0xaaa.openvpn,0xaaa.wireguard,0xbbb.wireguard" {
+		t.Errorf("keys = %s", got)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	_, h := newTestAPI(t, Options{}, testProposals()...)
+
+	fields := "key format compatibility providerId serviceType quality{quality} contacts{type} accessPolicies{id}"
+	var tooComplex strings.Builder
+	tooComplex.WriteString("{")
+	for _, alias := range []string{"a", "b", "c", "d", "e", "f"} {
+		tooComplex.WriteString(alias + ":proposals(limit:1000){proposals{" + fields + "}} ")
+	}
+	tooComplex.WriteString("}")
+
+	tests := []struct {
+		name  string
+		query string
+		error string
+	}{
+		{"invalid sort", `{proposals(sort:"country"){next}}`, "unknown sort field"},
+		{"invalid cursor", `{proposals(after:"!"){next}}`, "invalid cursor"},
+		{"unknown field", `{unknown}`, "Cannot query field"},
+		{"too complex", tooComplex.String(), "maximum complexity"},
+		{"fragment cycle", `{...a} fragment a on Query{...b} fragment b on Query{...a}`, "Cannot spread fragment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := postGraphQL(t, h, tt.query, nil)
+			if len(res.Errors) == 0 {
+				t.Fatal("query succeeded, want an error")
+			}
+			if !strings.Contains(res.Errors[0].Message, tt.error) {
+				t.Errorf("error = %q, want %q", res.Errors[0].Message, tt.error)
+			}
+		})
+	}
+}
+
+func TestGraphQLRequestErrors(t *testing.T) {
+	_, h := newTestAPI(t, Options{})
+
+	tests := []struct {
+		method string
+		target string
+		body   string
+	}{
+		{http.MethodGet, "/graphql", ""},
+		{http.MethodGet, "/graphql?query=" + url.QueryEscape("{providers{id}}") + "&variables=nope", ""},
+		{http.MethodPost, "/graphql", "not json"},
+		{http.MethodPost, "/graphql", `{"variables":{}}`},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s %s %q: status = %d, want 400", tt.method, tt.target, tt.body, rec.Code)
+		}
+	}
+}
@@ -1,45 +1,120 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sch8ill/propmon/analytics"
+	"github.com/sch8ill/propmon/geo"
 	"github.com/sch8ill/propmon/proposal"
+	"github.com/sch8ill/propmon/stats"
 )
 
 const maxResponseCount = 1000
 
 type handler struct {
-	repository *proposal.Repository
+	repository      *proposal.Repository
+	stats           *stats.Cache
+	analytics       *analytics.Service
+	locator         *geo.Locator
+	eventBufferSize int
 }
 
-func newHandler(repository *proposal.Repository) *handler {
-	return &handler{repository: repository}
+func newHandler(repository *proposal.Repository, stats *stats.Cache, analytics *analytics.Service, locator *geo.Locator, eventBufferSize int) *handler {
+	return &handler{
+		repository:      repository,
+		stats:           stats,
+		analytics:       analytics,
+		locator:         locator,
+		eventBufferSize: eventBufferSize,
+	}
 }
 
 func (h *handler) getProposals(c *gin.Context) {
 	max, err := strconv.Atoi(c.Query("max"))
-	if err != nil {
+	if err != nil || max < 1 {
 		max = 100
 	}
 	if max > maxResponseCount {
 		max = maxResponseCount
 	}
 
-	filter := &proposal.Proposal{
-		ProviderID:  c.Query("id"),
-		ServiceType: c.Query("service"),
-		Location: proposal.Location{
-			Country: c.Query("country"),
-			IpType:  c.Query("type"),
-		},
+	filter, err := proposal.ParseFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	proposals := h.repository.Match(filter, max)
-	if len(proposals) == 0 {
-		c.Status(http.StatusNotFound)
+	sort, err := proposal.ParseSort(c.Query("sort"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+
+	generation := h.repository.Generation()
+	if notModified(c, generationETag(generation.ID), generation.Modified, cacheRevalidate) {
+		return
+	}
+
+	proposals, next, err := h.repository.Query(proposal.Query{
+		Filter: filter,
+		Sort:   sort,
+		Cursor: c.Query("cursor"),
+		Limit:  max,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if next != "" {
+		c.Header("Link", fmt.Sprintf("<%s>; rel=\"next\"", nextURL(c, next)))
+	}
+
+	if proposals == nil {
+		proposals = []*proposal.Proposal{}
+	}
 	c.JSON(http.StatusOK, proposals)
 }
+
+func (h *handler) getStats(c *gin.Context) {
+	s := h.stats.Get()
+
+	// the stats only change when the cache is refreshed, so clients may keep them until then
+	maxAge := max(h.stats.TTL()-time.Since(s.GeneratedAt), 0)
+	etag := `W/"s` + strconv.FormatInt(s.GeneratedAt.UnixNano(), 10) + `"`
+	if notModified(c, etag, s.GeneratedAt, fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds()))) {
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+func (h *handler) getConcentration(c *gin.Context) {
+	report := h.analytics.Report()
+	if report == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "no concentration report has been computed yet"})
+		return
+	}
+
+	// reports only change on the analytics interval
+	maxAge := max(h.analytics.Interval()-time.Since(report.GeneratedAt), 0)
+	etag := `W/"c` + strconv.FormatInt(report.GeneratedAt.UnixNano(), 10) + `"`
+	if notModified(c, etag, report.GeneratedAt, fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds()))) {
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func nextURL(c *gin.Context, cursor string) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Set("cursor", cursor)
+	u.RawQuery = query.Encode()
+
+	return u.RequestURI()
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func TestProposalsLimit(t *testing.T) {
+	proposals := make([]*proposal.Proposal, 0, maxResponseCount+5)
+	for i := range maxResponseCount + 5 {
+		proposals = append(proposals, &proposal.Proposal{ProviderID: fmt.Sprintf("0x%04d", i), ServiceType: "wireguard"})
+	}
+	_, h := newTestAPI(t, Options{}, proposals...)
+
+	tests := []struct {
+		max  string
+		want int
+	}{
+		{"", 100},
+		{"abc", 100},
+		{"0", 100},
+		{"-1", 100},
+		{"5", 5},
+		{"2000", maxResponseCount},
+	}
+
+	for _, tt := range tests {
+		t.Run("max="+tt.max, func(t *testing.T) {
+			rec := serve(h, http.MethodGet, "/api/v1/proposals?max="+tt.max, nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d", rec.Code)
+			}
+
+			var got []json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("got %d proposals, want %d", len(got), tt.want)
+			}
+			if rec.Header().Get("Link") == "" {
+				t.Error("Link header to the next page is missing")
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+var (
+	pathParam = regexp.MustCompile(`:([^/]+)`)
+
+	openAPIETag     = fmt.Sprintf(`"%x"`, sha256.Sum256(openAPISpec))
+	openAPIModified = time.Now()
+)
+
+func getOpenAPI(c *gin.Context) {
+	if notModified(c, openAPIETag, openAPIModified, cacheImmutable) {
+		return
+	}
+	c.Data(http.StatusOK, "application/json", openAPISpec)
+}
+
+// validateOpenAPI checks that every registered route is described by the openapi document,
+// so the document does not silently fall behind the handlers.
+func validateOpenAPI(routes gin.RoutesInfo) error {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		return fmt.Errorf("invalid openapi document: %w", err)
+	}
+
+	var missing []string
+	for _, route := range routes {
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		if _, ok := spec.Paths[path][strings.ToLower(route.Method)]; !ok {
+			missing = append(missing, route.Method+" "+path)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("routes missing from the openapi document: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func (a *API) validateOpenAPI() {
+	for _, s := range a.servers {
+		if err := validateOpenAPI(s.engine.Routes()); err != nil {
+			log.Warn().Err(err).Msg("OpenAPI document is out of date")
+		}
+	}
+}
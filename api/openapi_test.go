@@ -0,0 +1,372 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sch8ill/propmon/analytics"
+	"github.com/sch8ill/propmon/metrics"
+	"github.com/sch8ill/propmon/proposal"
+	"github.com/sch8ill/propmon/proposal/expiration"
+	"github.com/sch8ill/propmon/quality"
+	"github.com/sch8ill/propmon/stats"
+)
+
+// openAPIDocument is the part of the openapi document the contract tests check responses against.
+type openAPIDocument struct {
+	Paths map[string]map[string]struct {
+		Responses map[string]struct {
+			Content map[string]struct {
+				Schema *schema `json:"schema"`
+			} `json:"content"`
+		} `json:"responses"`
+	} `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// schema is the subset of the openapi 3.0 schema object used by the document.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	AllOf                []*schema          `json:"allOf"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Nullable             bool               `json:"nullable"`
+	Enum                 []any              `json:"enum"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+}
+
+func loadOpenAPI(t *testing.T) *openAPIDocument {
+	t.Helper()
+
+	var doc openAPIDocument
+	if err := json.Unmarshal(openAPISpec, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return &doc
+}
+
+// validate checks the decoded json value against the schema. Objects with declared properties
+// must not contain undeclared ones, so fields added to a response have to be documented.
+func (d *openAPIDocument) validate(s *schema, v any, path string) error {
+	if s.Ref != "" {
+		ref, ok := d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			return fmt.Errorf("%s: unknown schema %s", path, s.Ref)
+		}
+		return d.validate(ref, v, path)
+	}
+
+	if v == nil {
+		if s.Nullable || (s.Type == "" && len(s.AllOf) == 0) {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not allowed", path)
+	}
+
+	for _, sub := range s.AllOf {
+		if err := d.validate(sub, v, path); err != nil {
+			return err
+		}
+	}
+
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, v) {
+		return fmt.Errorf("%s: %v is not one of %v", path, v, s.Enum)
+	}
+
+	switch s.Type {
+	case "":
+		return nil
+
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: %v is not a string", path, v)
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				return fmt.Errorf("%s: %q is not a date-time", path, str)
+			}
+		}
+
+	case "number", "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: %v is not a number", path, v)
+		}
+		if s.Type == "integer" {
+			if _, err := strconv.ParseInt(n.String(), 10, 64); err != nil {
+				return fmt.Errorf("%s: %v is not an integer", path, n)
+			}
+		}
+
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: %v is not a boolean", path, v)
+		}
+
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: %v is not an array", path, v)
+		}
+		if s.MinItems != nil && len(items) < *s.MinItems || s.MaxItems != nil && len(items) > *s.MaxItems {
+			return fmt.Errorf("%s: %d items are out of bounds", path, len(items))
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				if err := d.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: %v is not an object", path, v)
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: required property %q is missing", path, name)
+			}
+		}
+		for name, value := range obj {
+			prop, ok := s.Properties[name]
+			switch {
+			case ok:
+			case len(s.AdditionalProperties) > 0:
+				prop = &schema{}
+				if err := json.Unmarshal(s.AdditionalProperties, prop); err != nil {
+					// additionalProperties: true allows any value
+					prop = &schema{}
+				}
+			case len(s.Properties) > 0:
+				return fmt.Errorf("%s: property %q is not documented", path, name)
+			default:
+				continue
+			}
+			if err := d.validate(prop, value, path+"."+name); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", path, s.Type)
+	}
+
+	return nil
+}
+
+// check validates a response against the documented response of the operation.
+func (d *openAPIDocument) check(path, method string, rec *httptest.ResponseRecorder) error {
+	op, ok := d.Paths[path][strings.ToLower(method)]
+	if !ok {
+		return fmt.Errorf("%s %s is not documented", method, path)
+	}
+
+	res, ok := op.Responses[strconv.Itoa(rec.Code)]
+	if !ok {
+		return fmt.Errorf("status %d of %s %s is not documented", rec.Code, method, path)
+	}
+
+	if len(res.Content) == 0 {
+		if rec.Body.Len() > 0 {
+			return fmt.Errorf("undocumented body in status %d of %s %s", rec.Code, method, path)
+		}
+		return nil
+	}
+
+	contentType, _, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("invalid content type: %w", err)
+	}
+	content, ok := res.Content[contentType]
+	if !ok {
+		return fmt.Errorf("content type %s of %s %s is not documented", contentType, method, path)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(rec.Body.Bytes()))
+	decoder.UseNumber()
+	var body any
+	if err := decoder.Decode(&body); err != nil {
+		return fmt.Errorf("invalid json body: %w", err)
+	}
+
+	return d.validate(content.Schema, body, "body")
+}
+
+func newContractAPI(t *testing.T) (*API, http.Handler) {
+	t.Helper()
+
+	oracle := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"0xaaa": {"quality": 3, "latency": 10, "bandwidth": 50, "uptime": 24}}`))
+	}))
+	t.Cleanup(oracle.Close)
+
+	repository := proposal.NewProposalRepository(time.Hour)
+	for _, p := range testProposals() {
+		repository.Store(p)
+	}
+
+	analyticsService := analytics.NewAnalyticsService(repository, time.Hour, 5)
+	analyticsService.Start()
+	t.Cleanup(analyticsService.Stop)
+
+	// every surface is served by the same router
+	options := Options{
+		Metrics:   Listener{Address: ":9090"},
+		Admin:     Listener{Address: ":9090"},
+		APIKeys:   testAPIKeys(),
+		Watchlist: metrics.NewWatchlist([]string{"0xaaa"}),
+		Analytics: analyticsService,
+	}
+
+	a := New(repository, stats.NewCache(repository, time.Minute),
+		quality.NewQualityService(quality.NewOracle(oracle.URL), repository, time.Hour, time.Hour),
+		expiration.NewExpirationService(repository, time.Hour), options)
+	if err := a.register(); err != nil {
+		t.Fatal(err)
+	}
+
+	return a, a.servers[":9090"].engine
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	a, _ := newContractAPI(t)
+	routes := a.servers[":9090"].engine.Routes()
+
+	if err := validateOpenAPI(routes); err != nil {
+		t.Fatal(err)
+	}
+
+	// and the other way around, every documented operation is served
+	registered := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		registered[route.Method+" "+pathParam.ReplaceAllString(route.Path, "{$1}")] = true
+	}
+	for path, ops := range loadOpenAPI(t).Paths {
+		for method := range ops {
+			if !registered[strings.ToUpper(method)+" "+path] {
+				t.Errorf("documented operation %s %s is not served", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestOpenAPIValidateMissingRoute(t *testing.T) {
+	routes := gin.RoutesInfo{{Method: http.MethodGet, Path: "/api/v1/undocumented"}}
+	if err := validateOpenAPI(routes); err == nil {
+		t.Error("validateOpenAPI accepted an undocumented route")
+	}
+}
+
+func TestOpenAPIContract(t *testing.T) {
+	_, h := newContractAPI(t)
+	doc := loadOpenAPI(t)
+
+	read := http.Header{"X-Api-Key": {"read-key"}}
+	admin := http.Header{"X-Api-Key": {"admin-key"}}
+
+	tests := []struct {
+		method string
+		// path is the documented path, target the requested url
+		path   string
+		target string
+		header http.Header
+		status int
+	}{
+		{"GET", "/api/v1/proposals", "/api/v1/proposals", read, http.StatusOK},
+		{"GET", "/api/v1/proposals", "/api/v1/proposals?country=FR", read, http.StatusOK},
+		{"GET", "/api/v1/proposals", "/api/v1/proposals?max=1&sort=-quality", read, http.StatusOK},
+		{"GET", "/api/v1/proposals", "/api/v1/proposals?asn=abc", read, http.StatusBadRequest},
+		{"GET", "/api/v1/proposals", "/api/v1/proposals", nil, http.StatusUnauthorized},
+		{"GET", "/api/v1/stats", "/api/v1/stats", read, http.StatusOK},
+		{"GET", "/api/v1/concentration", "/api/v1/concentration", read, http.StatusOK},
+		{"GET", "/api/v1/export", "/api/v1/export?format=xml", read, http.StatusForbidden},
+		{"GET", "/api/v1/export", "/api/v1/export?format=xml", admin, http.StatusBadRequest},
+		{"GET", "/api/v1/providers.geojson", "/api/v1/providers.geojson", read, http.StatusOK},
+		{"GET", "/api/v1/countries.geojson", "/api/v1/countries.geojson", read, http.StatusOK},
+		{"GET", "/api/v1/openapi.json", "/api/v1/openapi.json", nil, http.StatusOK},
+		{"GET", "/graphql", "/graphql?query={proposals(first:1){totalCount}}", read, http.StatusOK},
+		{"GET", "/graphql", "/graphql?query={unknown}", read, http.StatusOK},
+		{"GET", "/graphql", "/graphql", read, http.StatusBadRequest},
+		{"GET", "/api/v4/proposals", "/api/v4/proposals", read, http.StatusOK},
+		{"GET", "/api/v4/proposals", "/api/v4/proposals?location_country=FR", read, http.StatusOK},
+		{"GET", "/api/v4/proposals", "/api/v4/proposals?quality_min=high", read, http.StatusBadRequest},
+		{"GET", "/metrics", "/metrics", read, http.StatusForbidden},
+		{"GET", "/admin/status", "/admin/status", admin, http.StatusOK},
+		{"GET", "/admin/status", "/admin/status", read, http.StatusForbidden},
+		{"GET", "/admin/debug", "/admin/debug", admin, http.StatusOK},
+		{"PUT", "/admin/proposals/{key}/pin", "/admin/proposals/0xaaa.wireguard/pin", admin, http.StatusOK},
+		{"PUT", "/admin/proposals/{key}/pin", "/admin/proposals/unknown/pin", admin, http.StatusNotFound},
+		{"DELETE", "/admin/proposals/{key}/pin", "/admin/proposals/0xaaa.wireguard/pin", admin, http.StatusOK},
+		{"POST", "/admin/quality/refresh", "/admin/quality/refresh", admin, http.StatusOK},
+		{"POST", "/admin/expiration/sweep", "/admin/expiration/sweep", admin, http.StatusOK},
+		{"GET", "/admin/watchlist", "/admin/watchlist", admin, http.StatusOK},
+		{"PUT", "/admin/watchlist/{id}", "/admin/watchlist/0xbbb", admin, http.StatusOK},
+		{"DELETE", "/admin/watchlist/{id}", "/admin/watchlist/0xbbb", admin, http.StatusOK},
+		{"DELETE", "/admin/watchlist/{id}", "/admin/watchlist/0xbbb", admin, http.StatusNotFound},
+		{"DELETE", "/admin/providers/{id}", "/admin/providers/0xbbb", admin, http.StatusOK},
+		{"DELETE", "/admin/providers/{id}", "/admin/providers/0xbbb", admin, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			rec := serve(h, tt.method, tt.target, tt.header)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if err := doc.check(tt.path, tt.method, rec); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestOpenAPIValidateSchema(t *testing.T) {
+	doc := loadOpenAPI(t)
+	proposal := &schema{Ref: "#/components/schemas/Proposal"}
+
+	tests := []struct {
+		name  string
+		body  string
+		valid bool
+	}{
+		{"valid", `{"format":"f","compatibility":1,"provider_id":"p","service_type":"s","location":{},"contacts":[],"quality":null}`, true},
+		{"missing required", `{"format":"f","compatibility":1,"provider_id":"p","service_type":"s","location":{},"contacts":[]}`, false},
+		{"wrong type", `{"format":"f","compatibility":"1","provider_id":"p","service_type":"s","location":{},"contacts":[],"quality":null}`, false},
+		{"not an integer", `{"format":"f","compatibility":1.5,"provider_id":"p","service_type":"s","location":{},"contacts":[],"quality":null}`, false},
+		{"undocumented property", `{"format":"f","compatibility":1,"provider_id":"p","service_type":"s","location":{},"contacts":[],"quality":null,"x":1}`, false},
+	}
+
+	for _, tt := range tests {
+		decoder := json.NewDecoder(strings.NewReader(tt.body))
+		decoder.UseNumber()
+		var body any
+		if err := decoder.Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := doc.validate(proposal, body, "body"); (err == nil) != tt.valid {
+			t.Errorf("%s: validate = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
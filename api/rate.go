@@ -1,38 +1,62 @@
 package api
 
 import (
+	"container/list"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/sch8ill/propmon/config"
+	"github.com/sch8ill/propmon/metrics"
 )
 
+// rateLimiter is a token bucket limiter per client. Buckets are kept in least recently used
+// order and the oldest one is dropped once maxClients is exceeded to bound memory usage.
 type rateLimiter struct {
-	max      int
-	period   time.Duration
-	requests map[string]int
-	mu       sync.RWMutex
+	group      string
+	rate       float64
+	burst      float64
+	maxClients int
+	buckets    map[string]*list.Element
+	lru        *list.List
+	mu         sync.Mutex
+}
+
+type bucket struct {
+	key     string
+	tokens  float64
+	updated time.Time
 }
 
-func newRateLimiter(max int, period time.Duration) *rateLimiter {
-	limiter := &rateLimiter{
-		max:      max,
-		period:   period,
-		requests: make(map[string]int),
+func newRateLimiter(group string, limit config.RateLimit, maxClients int) *rateLimiter {
+	return &rateLimiter{
+		group:      group,
+		rate:       float64(limit.Requests) / limit.Period.Seconds(),
+		burst:      float64(limit.Burst),
+		maxClients: maxClients,
+		buckets:    make(map[string]*list.Element),
+		lru:        list.New(),
 	}
-	limiter.startPruner()
-	return limiter
 }
 
 func (r *rateLimiter) middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		r.mu.Lock()
-		r.requests[c.ClientIP()]++
-		count := r.requests[c.ClientIP()]
-		r.mu.Unlock()
+		allowed, remaining, retryAfter := r.take(rateLimitKey(c))
+
+		// time until the bucket is full again
+		reset := (r.burst - remaining) / r.rate
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(int(r.burst)))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(int(remaining)))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(reset))))
 
-		if count > r.max {
+		if !allowed {
+			metrics.RateLimited(r.group)
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
 			c.AbortWithStatus(http.StatusTooManyRequests)
 			return
 		}
@@ -41,15 +65,51 @@ func (r *rateLimiter) middleware() gin.HandlerFunc {
 	}
 }
 
-func (r *rateLimiter) startPruner() {
-	go func() {
-		ticker := time.NewTicker(r.period)
-		defer ticker.Stop()
+// take removes a token from the bucket of the client. It returns whether the request is allowed,
+// the remaining tokens and the time until the next token is available.
+func (r *rateLimiter) take(key string) (bool, float64, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-		for range ticker.C {
-			r.mu.Lock()
-			r.requests = make(map[string]int)
-			r.mu.Unlock()
-		}
-	}()
+	now := time.Now()
+	b := r.bucket(key, now)
+
+	b.tokens = math.Min(r.burst, b.tokens+now.Sub(b.updated).Seconds()*r.rate)
+	b.updated = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / r.rate * float64(time.Second))
+		return false, b.tokens, wait
+	}
+
+	b.tokens--
+	return true, b.tokens, 0
+}
+
+func (r *rateLimiter) bucket(key string, now time.Time) *bucket {
+	if e, ok := r.buckets[key]; ok {
+		r.lru.MoveToFront(e)
+		return e.Value.(*bucket)
+	}
+
+	b := &bucket{key: key, tokens: r.burst, updated: now}
+	r.buckets[key] = r.lru.PushFront(b)
+
+	for r.lru.Len() > r.maxClients {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.buckets, oldest.Value.(*bucket).key)
+	}
+
+	return b
+}
+
+// rateLimitKey identifies the client of a request by its api key if it is authenticated
+// and by its ip address otherwise. The ip address is only taken from forwarding headers
+// if the request comes from a trusted proxy.
+func rateLimitKey(c *gin.Context) string {
+	if key := c.GetString(apiKeyContextKey); key != "" {
+		return "key:" + key
+	}
+	return "ip:" + c.ClientIP()
 }
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sch8ill/propmon/config"
+)
+
+func TestRateLimiterTake(t *testing.T) {
+	r := newRateLimiter("api", config.RateLimit{Requests: 1, Period: time.Second, Burst: 3}, 10)
+
+	for i := range 3 {
+		if allowed, remaining, _ := r.take("a"); !allowed || int(remaining) != 2-i {
+			t.Fatalf("request %d: allowed = %v, remaining = %v", i, allowed, remaining)
+		}
+	}
+
+	allowed, _, retryAfter := r.take("a")
+	if allowed {
+		t.Fatal("request beyond the burst was allowed")
+	}
+	if retryAfter <= 0 || retryAfter > time.Second {
+		t.Errorf("retry after = %v, want up to a second", retryAfter)
+	}
+
+	// other clients have their own bucket
+	if allowed, _, _ := r.take("b"); !allowed {
+		t.Error("request of another client was rejected")
+	}
+
+	// tokens refill at the configured rate, but not beyond the burst
+	r.buckets["a"].Value.(*bucket).updated = time.Now().Add(-2 * time.Second)
+	if allowed, remaining, _ := r.take("a"); !allowed || remaining < 0.9 || remaining > 1.1 {
+		t.Errorf("after 2s: allowed = %v, remaining = %v, want about 1", allowed, remaining)
+	}
+	r.buckets["a"].Value.(*bucket).updated = time.Now().Add(-time.Hour)
+	if _, remaining, _ := r.take("a"); remaining != 2 {
+		t.Errorf("after an hour: remaining = %v, want 2", remaining)
+	}
+}
+
+func TestRateLimiterEviction(t *testing.T) {
+	r := newRateLimiter("api", config.RateLimit{Requests: 1, Period: time.Minute, Burst: 1}, 2)
+
+	r.take("a")
+	r.take("b")
+	r.take("a")
+	r.take("c")
+
+	if _, ok := r.buckets["b"]; ok {
+		t.Error("least recently used bucket was not evicted")
+	}
+	if len(r.buckets) != 2 || r.lru.Len() != 2 {
+		t.Errorf("%d buckets, want 2", len(r.buckets))
+	}
+
+	// the bucket of "a" survived and is still empty
+	if allowed, _, _ := r.take("a"); allowed {
+		t.Error("evicted the wrong bucket")
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter := newRateLimiter("api", config.RateLimit{Requests: 1, Period: time.Minute, Burst: 2}, 10)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if key := c.GetHeader("X-Test-Key"); key != "" {
+			c.Set(apiKeyContextKey, key)
+		}
+	})
+	router.GET("/", limiter.middleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name      string
+		key       string
+		status    int
+		remaining string
+	}{
+		{"first", "", http.StatusOK, "1"},
+		{"second", "", http.StatusOK, "0"},
+		{"limited", "", http.StatusTooManyRequests, "0"},
+		// authenticated clients are limited per key rather than per address
+		{"api key", "k1", http.StatusOK, "1"},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.key != "" {
+			header.Set("X-Test-Key", tt.key)
+		}
+
+		rec := serve(router, http.MethodGet, "/", header)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.status)
+		}
+		if got := rec.Header().Get("X-RateLimit-Limit"); got != "2" {
+			t.Errorf("%s: limit = %q, want 2", tt.name, got)
+		}
+		if got := rec.Header().Get("X-RateLimit-Remaining"); got != tt.remaining {
+			t.Errorf("%s: remaining = %q, want %q", tt.name, got, tt.remaining)
+		}
+		if tt.status == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "60" {
+			t.Errorf("%s: retry after = %q, want 60", tt.name, rec.Header().Get("Retry-After"))
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+type Scope string
+
+const (
+	ScopeReadProposals Scope = "read:proposals"
+	ScopeReadExport    Scope = "read:export"
+	ScopeReadMetrics   Scope = "read:metrics"
+	// ScopeAdmin grants every other scope
+	ScopeAdmin Scope = "admin"
+)
+
+// APIKey grants the holder of the key access to the given scopes. Instead of the key itself,
+// the key file may contain its hex encoded sha256 hash.
+type APIKey struct {
+	Name      string  `json:"name"`
+	Key       string  `json:"key,omitempty"`
+	KeySHA256 string  `json:"key_sha256,omitempty"`
+	Scopes    []Scope `json:"scopes"`
+}
+
+// HasScope reports whether the key grants the scope.
+func (k *APIKey) HasScope(scope Scope) bool {
+	return slices.Contains(k.Scopes, scope) || slices.Contains(k.Scopes, ScopeAdmin)
+}
+
+// LoadAPIKeys reads a json array of api keys.
+func LoadAPIKeys(path string) ([]APIKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read api keys: %w", err)
+	}
+
+	var keys []APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Keyring looks up api keys by their value.
+type Keyring struct {
+	keys map[[sha256.Size]byte]*APIKey
+}
+
+func NewKeyring(keys []APIKey) (*Keyring, error) {
+	k := &Keyring{keys: make(map[[sha256.Size]byte]*APIKey)}
+
+	for i := range keys {
+		key := &keys[i]
+		if key.Name == "" {
+			return nil, fmt.Errorf("api key %d has no name", i)
+		}
+
+		var hash [sha256.Size]byte
+		switch {
+		case key.Key != "":
+			hash = sha256.Sum256([]byte(key.Key))
+		case key.KeySHA256 != "":
+			decoded, err := hex.DecodeString(key.KeySHA256)
+			if err != nil || len(decoded) != sha256.Size {
+				return nil, fmt.Errorf("api key %q has an invalid sha256 hash", key.Name)
+			}
+			copy(hash[:], decoded)
+		default:
+			return nil, fmt.Errorf("api key %q has no key", key.Name)
+		}
+
+		for _, scope := range key.Scopes {
+			switch scope {
+			case ScopeReadProposals, ScopeReadExport, ScopeReadMetrics, ScopeAdmin:
+			default:
+				return nil, fmt.Errorf("api key %q has unknown scope %q", key.Name, scope)
+			}
+		}
+
+		if _, ok := k.keys[hash]; ok {
+			return nil, fmt.Errorf("api key %q is used more than once", key.Name)
+		}
+		k.keys[hash] = key
+	}
+
+	return k, nil
+}
+
+// Empty reports whether the keyring contains no keys, in which case authentication is disabled.
+func (k *Keyring) Empty() bool {
+	return len(k.keys) == 0
+}
+
+// Lookup returns the api key with the value token.
+func (k *Keyring) Lookup(token string) (*APIKey, bool) {
+	key, ok := k.keys[sha256.Sum256([]byte(token))]
+	return key, ok
+}
+
+// HasScope reports whether any key of the keyring grants the scope.
+func (k *Keyring) HasScope(scope Scope) bool {
+	for _, key := range k.keys {
+		if key.HasScope(scope) {
+			return true
+		}
+	}
+	return false
+}
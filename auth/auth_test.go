@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testAPIKeys() []APIKey {
+	return []APIKey{
+		{Name: "reader", Key: "read-key", Scopes: []Scope{ScopeReadProposals}},
+		// sha256 of "export-key"
+		{Name: "exporter", KeySHA256: "df1bc0d03fdb06b2fbdb0f76956cad1856e1f4ceab7f8fa40c40beec226a0e3d", Scopes: []Scope{ScopeReadExport}},
+	}
+}
+
+func TestNewKeyringErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []APIKey
+	}{
+		{"no name", []APIKey{{Key: "k"}}},
+		{"no key", []APIKey{{Name: "a"}}},
+		{"invalid hash", []APIKey{{Name: "a", KeySHA256: "abc"}}},
+		{"unknown scope", []APIKey{{Name: "a", Key: "k", Scopes: []Scope{"write:proposals"}}}},
+		{"duplicate key", []APIKey{{Name: "a", Key: "k"}, {Name: "b", Key: "k"}}},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewKeyring(tt.keys); err == nil {
+			t.Errorf("%s: NewKeyring succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestKeyringLookup(t *testing.T) {
+	keyring, err := NewKeyring(testAPIKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		token string
+		name  string
+	}{
+		{"read-key", "reader"},
+		{"export-key", "exporter"},
+		{"unknown", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		key, ok := keyring.Lookup(tt.token)
+		if ok != (tt.name != "") || (ok && key.Name != tt.name) {
+			t.Errorf("Lookup(%q) = %v, %v, want %q", tt.token, key, ok, tt.name)
+		}
+	}
+}
+
+func TestAPIKeyHasScope(t *testing.T) {
+	tests := []struct {
+		scopes []Scope
+		scope  Scope
+		want   bool
+	}{
+		{[]Scope{ScopeReadProposals}, ScopeReadProposals, true},
+		{[]Scope{ScopeReadProposals}, ScopeReadExport, false},
+		{[]Scope{ScopeReadExport, ScopeReadMetrics}, ScopeReadMetrics, true},
+		{[]Scope{ScopeAdmin}, ScopeReadExport, true},
+		{nil, ScopeReadProposals, false},
+	}
+
+	for _, tt := range tests {
+		key := &APIKey{Scopes: tt.scopes}
+		if got := key.HasScope(tt.scope); got != tt.want {
+			t.Errorf("%v HasScope(%s) = %v, want %v", tt.scopes, tt.scope, got, tt.want)
+		}
+	}
+}
+
+func TestKeyringHasScope(t *testing.T) {
+	keyring, err := NewKeyring(testAPIKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		scope Scope
+		want  bool
+	}{
+		{ScopeReadProposals, true},
+		{ScopeReadExport, true},
+		{ScopeReadMetrics, false},
+		{ScopeAdmin, false},
+	}
+
+	for _, tt := range tests {
+		if got := keyring.HasScope(tt.scope); got != tt.want {
+			t.Errorf("HasScope(%s) = %v, want %v", tt.scope, got, tt.want)
+		}
+	}
+
+	empty, err := NewKeyring(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !empty.Empty() || empty.HasScope(ScopeReadProposals) {
+		t.Error("empty keyring grants a scope")
+	}
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "keys.json")
+	if err := os.WriteFile(valid, []byte(`[{"name":"reader","key":"k","scopes":["read:proposals"]}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := LoadAPIKeys(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0].Name != "reader" || !keys[0].HasScope(ScopeReadProposals) {
+		t.Errorf("keys = %+v", keys)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"name":"reader"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{invalid, filepath.Join(dir, "missing.json")} {
+		if _, err := LoadAPIKeys(path); err == nil {
+			t.Errorf("LoadAPIKeys(%s) succeeded, want an error", filepath.Base(path))
+		}
+	}
+}
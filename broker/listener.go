@@ -21,16 +21,18 @@ type Listener struct {
 	brokerUrl  string
 	conn       *nats.Conn
 	repository *proposal.Repository
+	watchlist  *metrics.Watchlist
 }
 
 type Msg struct {
 	Proposal *proposal.Proposal
 }
 
-func NewListener(brokerUrl string, repository *proposal.Repository) *Listener {
+func NewListener(brokerUrl string, repository *proposal.Repository, watchlist *metrics.Watchlist) *Listener {
 	return &Listener{
 		brokerUrl:  brokerUrl,
 		repository: repository,
+		watchlist:  watchlist,
 	}
 }
 
@@ -66,6 +68,7 @@ func (l *Listener) onPing(msg *nats.Msg) {
 		return
 	}
 	l.repository.RenewOrStore(p)
+	l.watchlist.Ping(p.ProviderID)
 	metrics.ProposalPing()
 }
 
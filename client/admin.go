@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+type Status struct {
+	Uptime    string `json:"uptime"`
+	Proposals int    `json:"proposals"`
+	Providers int    `json:"providers"`
+}
+
+func (c *Client) Status(ctx context.Context) (*Status, error) {
+	var status Status
+	if err := c.do(ctx, http.MethodGet, "/admin/status", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (c *Client) DebugStats(ctx context.Context) (*proposal.DebugStats, error) {
+	var stats proposal.DebugStats
+	if err := c.do(ctx, http.MethodGet, "/admin/debug", nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// EvictProvider removes all proposals of the provider and returns how many were removed.
+func (c *Client) EvictProvider(ctx context.Context, id string) (int, error) {
+	var res struct {
+		Removed int `json:"removed"`
+	}
+	if err := c.do(ctx, http.MethodDelete, "/admin/providers/"+url.PathEscape(id), nil, &res); err != nil {
+		return 0, err
+	}
+	return res.Removed, nil
+}
+
+// PinProposal pins the proposal with the key (<provider id>.<service type>) so it never expires.
+func (c *Client) PinProposal(ctx context.Context, key string) error {
+	return c.do(ctx, http.MethodPut, "/admin/proposals/"+url.PathEscape(key)+"/pin", nil, &struct{}{})
+}
+
+func (c *Client) UnpinProposal(ctx context.Context, key string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/proposals/"+url.PathEscape(key)+"/pin", nil, &struct{}{})
+}
+
+// RefreshQuality fetches quality data immediately and returns the number of entries.
+func (c *Client) RefreshQuality(ctx context.Context) (int, error) {
+	var res struct {
+		Entries int `json:"entries"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/admin/quality/refresh", nil, &res); err != nil {
+		return 0, err
+	}
+	return res.Entries, nil
+}
+
+// SweepExpired removes expired proposals immediately and returns how many expired.
+func (c *Client) SweepExpired(ctx context.Context) (int, error) {
+	var res struct {
+		Expired int `json:"expired"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/admin/expiration/sweep", nil, &res); err != nil {
+		return 0, err
+	}
+	return res.Expired, nil
+}
+
+// Watchlist returns the ids of the providers exported individually in the metrics.
+func (c *Client) Watchlist(ctx context.Context) ([]string, error) {
+	var res struct {
+		Providers []string `json:"providers"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/watchlist", nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Providers, nil
+}
+
+// WatchProvider adds the provider to the watchlist and reports whether it was not watched before.
+func (c *Client) WatchProvider(ctx context.Context, id string) (bool, error) {
+	var res struct {
+		Added bool `json:"added"`
+	}
+	if err := c.do(ctx, http.MethodPut, "/admin/watchlist/"+url.PathEscape(id), nil, &res); err != nil {
+		return false, err
+	}
+	return res.Added, nil
+}
+
+func (c *Client) UnwatchProvider(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/watchlist/"+url.PathEscape(id), nil, &struct{}{})
+}
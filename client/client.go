@@ -0,0 +1,254 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sch8ill/propmon/analytics"
+	"github.com/sch8ill/propmon/proposal"
+	"github.com/sch8ill/propmon/stats"
+)
+
+// Client is a typed client of the propmon api. The admin endpoints are served on a separate
+// listener, so admin methods need a client created with the address of the admin api.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type Option func(c *Client)
+
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Error is returned for responses with an unexpected status code.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("propmon api: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("propmon api: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+func (c *Client) request(ctx context.Context, method string, path string, query url.Values) (*http.Response, error) {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.send(req)
+}
+
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("propmon api request failed: %w", err)
+	}
+
+	return res, nil
+}
+
+// do performs a request and decodes the json response into v.
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, v any) error {
+	res, err := c.request(ctx, method, path, query)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return responseError(res)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode propmon api response: %w", err)
+	}
+
+	return nil
+}
+
+func responseError(res *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(res.Body, 1<<16))
+	_ = json.Unmarshal(data, &body)
+
+	return &Error{StatusCode: res.StatusCode, Message: body.Error}
+}
+
+type ProposalsQuery struct {
+	Filter *proposal.Filter
+	// Sort is a sort field, prefixed with "-" for descending order
+	Sort   string
+	Cursor string
+	Max    int
+}
+
+type ProposalsPage struct {
+	Proposals []*proposal.Proposal
+	// Next is the cursor of the next page, it is empty on the last page
+	Next string
+}
+
+func (c *Client) Proposals(ctx context.Context, q ProposalsQuery) (*ProposalsPage, error) {
+	query := q.Filter.Values()
+	if q.Sort != "" {
+		query.Set("sort", q.Sort)
+	}
+	if q.Cursor != "" {
+		query.Set("cursor", q.Cursor)
+	}
+	if q.Max > 0 {
+		query.Set("max", fmt.Sprint(q.Max))
+	}
+
+	res, err := c.request(ctx, http.MethodGet, "/api/v1/proposals", query)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, responseError(res)
+	}
+
+	page := &ProposalsPage{Next: nextCursor(res.Header.Get("Link"))}
+
+	if err := json.NewDecoder(res.Body).Decode(&page.Proposals); err != nil {
+		return nil, fmt.Errorf("failed to decode propmon api response: %w", err)
+	}
+
+	return page, nil
+}
+
+func nextCursor(link string) string {
+	for _, l := range strings.Split(link, ",") {
+		target, params, _ := strings.Cut(strings.TrimSpace(l), ";")
+		if !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+
+		u, err := url.Parse(strings.Trim(target, "<>"))
+		if err != nil {
+			return ""
+		}
+		return u.Query().Get("cursor")
+	}
+
+	return ""
+}
+
+func (c *Client) Stats(ctx context.Context) (*stats.Stats, error) {
+	var s stats.Stats
+	if err := c.do(ctx, http.MethodGet, "/api/v1/stats", nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Concentration returns the last network concentration report.
+func (c *Client) Concentration(ctx context.Context) (*analytics.Report, error) {
+	var report analytics.Report
+	if err := c.do(ctx, http.MethodGet, "/api/v1/concentration", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Export streams the proposals matching the filter in the format (csv, ndjson or parquet).
+// The caller must close the returned reader.
+func (c *Client) Export(ctx context.Context, format string, filter *proposal.Filter) (io.ReadCloser, error) {
+	query := filter.Values()
+	query.Set("format", format)
+
+	res, err := c.request(ctx, http.MethodGet, "/api/v1/export", query)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, responseError(res)
+	}
+
+	return res.Body, nil
+}
+
+// DiscoveryProposals queries the discovery compatible endpoint with discovery query parameters.
+func (c *Client) DiscoveryProposals(ctx context.Context, query url.Values) ([]*proposal.Proposal, error) {
+	var proposals []*proposal.Proposal
+	if err := c.do(ctx, http.MethodGet, "/api/v4/proposals", query, &proposals); err != nil {
+		return nil, err
+	}
+	return proposals, nil
+}
+
+func (c *Client) OpenAPI(ctx context.Context) (json.RawMessage, error) {
+	var spec json.RawMessage
+	if err := c.do(ctx, http.MethodGet, "/api/v1/openapi.json", nil, &spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Metrics returns the metrics in the prometheus text format.
+func (c *Client) Metrics(ctx context.Context) (string, error) {
+	res, err := c.request(ctx, http.MethodGet, "/metrics", nil)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", responseError(res)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read metrics: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func TestProposals(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		link     string
+		want     int
+		next     string
+		wantCode int
+	}{
+		{"page", http.StatusOK, `[{"provider_id":"0xaaa"},{"provider_id":"0xbbb"}]`,
+			`</api/v1/proposals?cursor=abc&max=2>; rel="next"`, 2, "abc", 0},
+		{"last page", http.StatusOK, `[{"provider_id":"0xaaa"}]`, "", 1, "", 0},
+		{"no results", http.StatusOK, `[]`, "", 0, "", 0},
+		{"not found", http.StatusNotFound, `{"error":"not found"}`, "", 0, "", http.StatusNotFound},
+		{"bad request", http.StatusBadRequest, `{"error":"invalid filter"}`, "", 0, "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.RawQuery
+				if tt.link != "" {
+					w.Header().Set("Link", tt.link)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			filter := proposal.NewFilter()
+			if err := filter.Where(proposal.FieldCountry, proposal.OpEqual, "DE"); err != nil {
+				t.Fatal(err)
+			}
+
+			page, err := New(srv.URL).Proposals(context.Background(), ProposalsQuery{Filter: filter, Sort: "-quality", Max: 2})
+			if query != "country=DE&max=2&sort=-quality" {
+				t.Errorf("query = %q", query)
+			}
+
+			if tt.wantCode != 0 {
+				var apiErr *Error
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantCode {
+					t.Fatalf("error = %v, want status %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Proposals) != tt.want || page.Next != tt.next {
+				t.Errorf("page = %d proposals, next %q, want %d, %q", len(page.Proposals), page.Next, tt.want, tt.next)
+			}
+		})
+	}
+}
+
+func TestNextCursor(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"", ""},
+		{`</api/v1/proposals?cursor=abc>; rel="next"`, "abc"},
+		{`</api/v1/proposals>; rel="first", </api/v1/proposals?cursor=x%2By>; rel="next"`, "x+y"},
+		{`</api/v1/proposals?cursor=abc>; rel="prev"`, ""},
+	}
+
+	for _, tt := range tests {
+		if got := nextCursor(tt.link); got != tt.want {
+			t.Errorf("nextCursor(%q) = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+}
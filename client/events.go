@@ -0,0 +1,87 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+// EventStream reads server-sent repository events.
+type EventStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	lastID  uint64
+}
+
+// Events subscribes to repository events matching the filter. If lastEventID is set, the stream
+// resumes after that event, or starts with a proposal.EventReset event if the server no longer
+// buffers all events after it.
+func (c *Client) Events(ctx context.Context, filter *proposal.Filter, lastEventID uint64) (*EventStream, error) {
+	query := filter.Values()
+	if lastEventID > 0 {
+		query.Set("last_event_id", strconv.FormatUint(lastEventID, 10))
+	}
+
+	res, err := c.request(ctx, http.MethodGet, "/api/v1/events", query)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, responseError(res)
+	}
+
+	return &EventStream{
+		body:    res.Body,
+		scanner: bufio.NewScanner(res.Body),
+		lastID:  lastEventID,
+	}, nil
+}
+
+// Next blocks until the next event arrives. It returns io.EOF once the server closes the stream.
+func (s *EventStream) Next() (*proposal.Event, error) {
+	var data strings.Builder
+
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+
+		if line == "" {
+			if data.Len() == 0 {
+				continue
+			}
+
+			var e proposal.Event
+			if err := json.Unmarshal([]byte(data.String()), &e); err != nil {
+				return nil, fmt.Errorf("failed to decode event: %w", err)
+			}
+			s.lastID = e.ID
+			return &e, nil
+		}
+
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data.WriteString(strings.TrimPrefix(value, " "))
+		}
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// LastEventID is the ID of the last received event, which can be used to resume the stream.
+func (s *EventStream) LastEventID() uint64 {
+	return s.lastID
+}
+
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sch8ill/propmon/geo"
+	"github.com/sch8ill/propmon/proposal"
+)
+
+// ProvidersGeoJSON returns the providers matching the filter as GeoJSON points.
+func (c *Client) ProvidersGeoJSON(ctx context.Context, filter *proposal.Filter) (*geo.FeatureCollection, error) {
+	var fc geo.FeatureCollection
+	if err := c.do(ctx, http.MethodGet, "/api/v1/providers.geojson", filter.Values(), &fc); err != nil {
+		return nil, err
+	}
+	return &fc, nil
+}
+
+// CountriesGeoJSON returns the providers matching the filter aggregated per country as GeoJSON points.
+func (c *Client) CountriesGeoJSON(ctx context.Context, filter *proposal.Filter) (*geo.FeatureCollection, error) {
+	var fc geo.FeatureCollection
+	if err := c.do(ctx, http.MethodGet, "/api/v1/countries.geojson", filter.Values(), &fc); err != nil {
+		return nil, err
+	}
+	return &fc, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -8,13 +9,21 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel"
 
+	"github.com/sch8ill/propmon/analytics"
 	"github.com/sch8ill/propmon/api"
+	"github.com/sch8ill/propmon/auth"
 	"github.com/sch8ill/propmon/broker"
 	"github.com/sch8ill/propmon/config"
+	"github.com/sch8ill/propmon/export"
+	"github.com/sch8ill/propmon/metrics"
 	"github.com/sch8ill/propmon/proposal"
 	"github.com/sch8ill/propmon/proposal/expiration"
+	"github.com/sch8ill/propmon/push"
 	"github.com/sch8ill/propmon/quality"
+	"github.com/sch8ill/propmon/rpc"
+	"github.com/sch8ill/propmon/stats"
 )
 
 func main() {
@@ -27,11 +36,16 @@ func main() {
 }
 
 func monitorProposals(ctx *cli.Context) error {
-	config.SetConfig(ctx)
+	if err := config.SetConfig(ctx); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
 
 	r := proposal.NewProposalRepository(config.ProposalLifetime)
 
-	listener := broker.NewListener(config.BrokerAddress, r)
+	watchlist := metrics.NewWatchlist(config.WatchProviders)
+	metrics.RegisterWatchlist(r, watchlist)
+
+	listener := broker.NewListener(config.BrokerAddress, r, watchlist)
 	if err := listener.Listen(); err != nil {
 		return fmt.Errorf("failed to start broker listener: %w", err)
 	}
@@ -46,9 +60,148 @@ func monitorProposals(ctx *cli.Context) error {
 	qualityService.Start()
 	defer qualityService.Stop()
 
-	apiServer := api.New(config.MetricsAddress, r)
-	if err := apiServer.Run(); err != nil {
-		return fmt.Errorf("api server: %w", err)
+	statsCache := stats.NewCache(r, config.StatsCacheTTL)
+
+	analyticsService := analytics.NewAnalyticsService(r, config.AnalyticsInterval, config.ConcentrationTop)
+	analyticsService.Start()
+	defer analyticsService.Stop()
+	metrics.RegisterAnalytics(analyticsService)
+
+	metrics.RegisterRepository(r, metrics.RepositoryOptions{
+		CacheTTL:     config.MetricsCacheTTL,
+		NetworkLimit: config.MetricsNetworkLimit,
+		ChurnWindows: config.ChurnWindows,
+	})
+
+	if config.OTLPEndpoint != "" {
+		// push failures are reported to the global error handler
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+			log.Warn().Err(err).Msg("Failed to push otlp metrics")
+		}))
+
+		exporter, err := metrics.NewOTLPExporter(metrics.OTLPOptions{
+			Endpoint:   config.OTLPEndpoint,
+			Protocol:   config.OTLPProtocol,
+			Insecure:   config.OTLPInsecure,
+			Interval:   config.OTLPInterval,
+			Headers:    config.OTLPHeaders,
+			Attributes: config.OTLPAttributes,
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := exporter.Shutdown(ctx); err != nil {
+				log.Warn().Err(err).Msg("Failed to shut down otlp exporter")
+			}
+		}()
+		log.Info().Str("endpoint", config.OTLPEndpoint).Str("protocol", config.OTLPProtocol).Msg("Pushing metrics via OTLP")
+	}
+
+	if config.PushURL != "" {
+		pusher, err := push.NewPusher(metrics.Registry, push.Options{
+			URL:         config.PushURL,
+			Mode:        config.PushMode,
+			Interval:    config.PushInterval,
+			Job:         config.PushJob,
+			Instance:    config.PushInstance,
+			BufferSize:  config.PushBufferSize,
+			Username:    config.PushUsername,
+			Password:    config.PushPassword,
+			BearerToken: config.PushBearerToken,
+		})
+		if err != nil {
+			return err
+		}
+		pusher.Start()
+		defer pusher.Stop()
+		log.Info().Str("url", config.PushURL).Str("mode", config.PushMode).Msg("Pushing metrics")
+	}
+
+	var apiKeys []auth.APIKey
+	if config.APIKeysFile != "" {
+		var err error
+		apiKeys, err = auth.LoadAPIKeys(config.APIKeysFile)
+		if err != nil {
+			return err
+		}
+		log.Info().Int("keys", len(apiKeys)).Msg("Loaded api keys")
+	}
+
+	apiServer := api.New(r, statsCache, qualityService, expirationService, api.Options{
+		Metrics: api.Listener{
+			Address: config.MetricsAddress,
+			TLSCert: config.MetricsTLSCert,
+			TLSKey:  config.MetricsTLSKey,
+		},
+		API: api.Listener{
+			Address: config.APIAddress,
+			TLSCert: config.APITLSCert,
+			TLSKey:  config.APITLSKey,
+		},
+		Admin: api.Listener{
+			Address: config.AdminAddress,
+			TLSCert: config.AdminTLSCert,
+			TLSKey:  config.AdminTLSKey,
+		},
+		EventBufferSize:     config.EventBufferSize,
+		RateLimits:          config.RateLimits,
+		RateLimitMaxClients: config.RateLimitMaxClients,
+		TrustedProxies:      config.TrustedProxies,
+		APIKeys:             apiKeys,
+		MetricsAllow:        config.MetricsAllow,
+		AuditLog:            config.AuditLog,
+		Compression:         config.Compression,
+		GeoCentroids:        config.GeoCentroids,
+		Watchlist:           watchlist,
+		Analytics:           analyticsService,
+	})
+
+	errCh := make(chan error, 2)
+	go func() {
+		errCh <- fmt.Errorf("api server: %w", apiServer.Run())
+	}()
+
+	if config.GRPCAddress != "" {
+		rpcServer, err := rpc.New(r, rpc.Options{
+			Address:         config.GRPCAddress,
+			TLSCert:         config.GRPCTLSCert,
+			TLSKey:          config.GRPCTLSKey,
+			EventBufferSize: config.EventBufferSize,
+			APIKeys:         apiKeys,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create grpc server: %w", err)
+		}
+		defer rpcServer.Stop()
+
+		go func() {
+			errCh <- fmt.Errorf("grpc server: %w", rpcServer.ListenAndServe())
+		}()
+	}
+
+	return <-errCh
+}
+
+func exportProposals(ctx *cli.Context) error {
+	format, err := export.ParseFormat(ctx.String(config.ExportFormatFlag))
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if output := ctx.String(config.ExportOutputFlag); output != "-" {
+		out, err = os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer out.Close()
+	}
+
+	if err := export.Convert(ctx.String(config.ExportSourceFlag), ctx.String(config.ExportAPIKeyFlag), format, out); err != nil {
+		return fmt.Errorf("export failed: %w", err)
 	}
 
 	return nil
@@ -61,6 +214,14 @@ func createApp() *cli.App {
 		Copyright: "Copyright (c) 2023 Sch8ill",
 		Action:    monitorProposals,
 		Flags:     config.DeclareFlags(),
+		Commands: []*cli.Command{
+			{
+				Name:   "export",
+				Usage:  "export proposals from a snapshot or a running instance as csv, ndjson or parquet",
+				Action: exportProposals,
+				Flags:  config.DeclareExportFlags(),
+			},
+		},
 	}
 }
 
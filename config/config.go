@@ -1,6 +1,11 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/urfave/cli/v2"
@@ -13,24 +18,182 @@ const (
 	DefaultExpirationJobInterval        = 20 * time.Second
 	DefaultQualityOracle                = "https://quality.mysterium.network"
 	DefaultQualityUpdateInterval        = 30 * time.Minute
+	DefaultStatsCacheTTL                = 10 * time.Second
+	DefaultMetricsCacheTTL              = 2 * time.Second
+	DefaultMetricsNetworkLimit          = 25
+	DefaultAnalyticsInterval            = 5 * time.Minute
+	DefaultConcentrationTop             = 10
+	DefaultOTLPProtocol                 = "grpc"
+	DefaultOTLPInterval                 = 30 * time.Second
+	DefaultPushMode                     = "remote-write"
+	DefaultPushInterval                 = 30 * time.Second
+	DefaultPushJob                      = "propmon"
+	DefaultPushBufferSize               = 120
+	DefaultEventBufferSize              = 256
+	DefaultRateLimitMaxClients          = 10000
 
 	BrokerAddressFlag         = "broker-address"
 	MetricsAddressFlag        = "metrics-address"
+	MetricsTLSCertFlag        = "metrics-tls-cert"
+	MetricsTLSKeyFlag         = "metrics-tls-key"
+	APIAddressFlag            = "api-address"
+	APITLSCertFlag            = "api-tls-cert"
+	APITLSKeyFlag             = "api-tls-key"
+	AdminAddressFlag          = "admin-address"
+	AdminTLSCertFlag          = "admin-tls-cert"
+	AdminTLSKeyFlag           = "admin-tls-key"
+	AuditLogFlag              = "audit-log"
+	GRPCAddressFlag           = "grpc-address"
+	GRPCTLSCertFlag           = "grpc-tls-cert"
+	GRPCTLSKeyFlag            = "grpc-tls-key"
 	ProposalLifetimeFlag      = "proposal-lifetime"
 	ExpirationJobIntervalFlag = "expiration-job-delay"
 	QualityOracleFlag         = "quality-oracle"
 	QualityUpdateIntervalFlag = "quality-update-interval"
+	StatsCacheTTLFlag         = "stats-cache-ttl"
+	MetricsCacheTTLFlag       = "metrics-cache-ttl"
+	MetricsNetworkLimitFlag   = "metrics-network-limit"
+	ChurnWindowFlag           = "churn-window"
+	AnalyticsIntervalFlag     = "analytics-interval"
+	ConcentrationTopFlag      = "concentration-top"
+	OTLPEndpointFlag          = "otlp-endpoint"
+	OTLPProtocolFlag          = "otlp-protocol"
+	OTLPInsecureFlag          = "otlp-insecure"
+	OTLPIntervalFlag          = "otlp-interval"
+	OTLPHeaderFlag            = "otlp-header"
+	OTLPAttributeFlag         = "otlp-resource-attribute"
+	PushURLFlag               = "push-url"
+	PushModeFlag              = "push-mode"
+	PushIntervalFlag          = "push-interval"
+	PushJobFlag               = "push-job"
+	PushInstanceFlag          = "push-instance"
+	PushBufferSizeFlag        = "push-buffer-size"
+	PushUsernameFlag          = "push-username"
+	PushPasswordFlag          = "push-password"
+	PushBearerTokenFlag       = "push-bearer-token"
+	EventBufferSizeFlag       = "event-buffer-size"
+	RateLimitFlag             = "rate-limit"
+	RateLimitMaxClientsFlag   = "rate-limit-max-clients"
+	TrustedProxiesFlag        = "trusted-proxies"
+	APIKeysFileFlag           = "api-keys-file"
+	MetricsAllowFlag          = "metrics-allow"
+	CompressionFlag           = "compression"
+	GeoCentroidsFlag          = "geo-centroids"
+	WatchProviderFlag         = "watch-provider"
+
+	ExportSourceFlag = "source"
+	ExportFormatFlag = "format"
+	ExportOutputFlag = "output"
+	ExportAPIKeyFlag = "api-key"
 )
 
 var (
 	BrokerAddress         string
 	MetricsAddress        string
+	MetricsTLSCert        string
+	MetricsTLSKey         string
+	APIAddress            string
+	APITLSCert            string
+	APITLSKey             string
+	AdminAddress          string
+	AdminTLSCert          string
+	AdminTLSKey           string
+	AuditLog              string
+	GRPCAddress           string
+	GRPCTLSCert           string
+	GRPCTLSKey            string
 	ProposalLifetime      time.Duration
 	ExpirationJobInterval time.Duration
 	QualityOracle         string
 	QualityUpdateInterval time.Duration
+	StatsCacheTTL         time.Duration
+	MetricsCacheTTL       time.Duration
+	MetricsNetworkLimit   int
+	ChurnWindows          []time.Duration
+	AnalyticsInterval     time.Duration
+	ConcentrationTop      int
+	OTLPEndpoint          string
+	OTLPProtocol          string
+	OTLPInsecure          bool
+	OTLPInterval          time.Duration
+	OTLPHeaders           map[string]string
+	OTLPAttributes        map[string]string
+	PushURL               string
+	PushMode              string
+	PushInterval          time.Duration
+	PushJob               string
+	PushInstance          string
+	PushBufferSize        int
+	PushUsername          string
+	PushPassword          string
+	PushBearerToken       string
+	EventBufferSize       int
+	RateLimits            map[string]RateLimit
+	RateLimitMaxClients   int
+	TrustedProxies        []string
+	APIKeysFile           string
+	MetricsAllow          []string
+	Compression           bool
+	GeoCentroids          string
+	WatchProviders        []string
 )
 
+var DefaultRateLimits = []string{"api=20/1m", "discovery=20/1m"}
+
+// RateLimitGroups are the route groups that can be rate limited.
+var RateLimitGroups = []string{"api", "discovery", "metrics", "admin"}
+
+var DefaultChurnWindows = []string{"1h", "24h"}
+
+// RateLimit allows Requests per Period with bursts of up to Burst requests.
+// The zero value disables rate limiting.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// ParseRateLimit parses a rate limit of a route group in the form group=requests/period[:burst]
+// or group=off.
+func ParseRateLimit(s string) (string, RateLimit, error) {
+	group, spec, ok := strings.Cut(s, "=")
+	if !ok {
+		return "", RateLimit{}, fmt.Errorf("invalid rate limit %q: expected group=requests/period[:burst]", s)
+	}
+	if !slices.Contains(RateLimitGroups, group) {
+		return "", RateLimit{}, fmt.Errorf("invalid rate limit %q: unknown group %q, expected api, discovery, metrics or admin", s, group)
+	}
+	if spec == "off" {
+		return group, RateLimit{}, nil
+	}
+
+	spec, burstSpec, hasBurst := strings.Cut(spec, ":")
+	requestsSpec, periodSpec, ok := strings.Cut(spec, "/")
+	if !ok {
+		return "", RateLimit{}, fmt.Errorf("invalid rate limit %q: expected group=requests/period[:burst]", s)
+	}
+
+	requests, err := strconv.Atoi(requestsSpec)
+	if err != nil || requests <= 0 {
+		return "", RateLimit{}, fmt.Errorf("invalid rate limit %q: invalid request count", s)
+	}
+
+	period, err := time.ParseDuration(periodSpec)
+	if err != nil || period <= 0 {
+		return "", RateLimit{}, fmt.Errorf("invalid rate limit %q: invalid period", s)
+	}
+
+	burst := requests
+	if hasBurst {
+		burst, err = strconv.Atoi(burstSpec)
+		if err != nil || burst <= 0 {
+			return "", RateLimit{}, fmt.Errorf("invalid rate limit %q: invalid burst", s)
+		}
+	}
+
+	return group, RateLimit{Requests: requests, Period: period, Burst: burst}, nil
+}
+
 func DeclareFlags() []cli.Flag {
 	return []cli.Flag{
 		&cli.StringFlag{
@@ -53,6 +216,54 @@ func DeclareFlags() []cli.Flag {
 			Usage: "address the prometheus metrics exporter listens on",
 			Value: DefaultMetricsAddress,
 		},
+		&cli.StringFlag{
+			Name:  MetricsTLSCertFlag,
+			Usage: "tls certificate file of the metrics listener",
+		},
+		&cli.StringFlag{
+			Name:  MetricsTLSKeyFlag,
+			Usage: "tls key file of the metrics listener",
+		},
+		&cli.StringFlag{
+			Name:  APIAddressFlag,
+			Usage: "address the public api listens on, defaults to the metrics address",
+		},
+		&cli.StringFlag{
+			Name:  APITLSCertFlag,
+			Usage: "tls certificate file of the api listener",
+		},
+		&cli.StringFlag{
+			Name:  APITLSKeyFlag,
+			Usage: "tls key file of the api listener",
+		},
+		&cli.StringFlag{
+			Name:  AdminAddressFlag,
+			Usage: "address the admin api listens on, the admin api is disabled if not set",
+		},
+		&cli.StringFlag{
+			Name:  AdminTLSCertFlag,
+			Usage: "tls certificate file of the admin listener",
+		},
+		&cli.StringFlag{
+			Name:  AdminTLSKeyFlag,
+			Usage: "tls key file of the admin listener",
+		},
+		&cli.StringFlag{
+			Name:  AuditLogFlag,
+			Usage: "file admin operations are recorded in, defaults to the application log",
+		},
+		&cli.StringFlag{
+			Name:  GRPCAddressFlag,
+			Usage: "address the grpc server listens on, the grpc server is disabled if not set",
+		},
+		&cli.StringFlag{
+			Name:  GRPCTLSCertFlag,
+			Usage: "tls certificate file of the grpc server",
+		},
+		&cli.StringFlag{
+			Name:  GRPCTLSKeyFlag,
+			Usage: "tls key file of the grpc server",
+		},
 		&cli.StringFlag{
 			Name:  QualityOracleFlag,
 			Usage: "url of the quality oracle",
@@ -63,14 +274,290 @@ func DeclareFlags() []cli.Flag {
 			Usage: "interval between quality data updates",
 			Value: DefaultQualityUpdateInterval,
 		},
+		&cli.DurationFlag{
+			Name:  StatsCacheTTLFlag,
+			Usage: "duration the statistics served by the api are cached",
+			Value: DefaultStatsCacheTTL,
+		},
+		&cli.DurationFlag{
+			Name:  MetricsCacheTTLFlag,
+			Usage: "duration the repository metrics are cached between scrapes",
+			Value: DefaultMetricsCacheTTL,
+		},
+		&cli.IntFlag{
+			Name:  MetricsNetworkLimitFlag,
+			Usage: "number of ASNs, ISPs, access policies and contact types exported individually, the others are folded into \"other\" (0 exports all)",
+			Value: DefaultMetricsNetworkLimit,
+		},
+		&cli.StringSliceFlag{
+			Name:  ChurnWindowFlag,
+			Usage: "window the churn rate is computed over, accurate to a minute",
+			Value: cli.NewStringSlice(DefaultChurnWindows...),
+		},
+		&cli.DurationFlag{
+			Name:  AnalyticsIntervalFlag,
+			Usage: "interval between computations of the network concentration indices",
+			Value: DefaultAnalyticsInterval,
+		},
+		&cli.IntFlag{
+			Name:  ConcentrationTopFlag,
+			Usage: "number of largest ASNs, ISPs and countries the top share is computed for",
+			Value: DefaultConcentrationTop,
+		},
+		&cli.StringFlag{
+			Name:  OTLPEndpointFlag,
+			Usage: "host:port or url of an opentelemetry collector the metrics are pushed to, disabled if not set",
+		},
+		&cli.StringFlag{
+			Name:  OTLPProtocolFlag,
+			Usage: "protocol of the otlp exporter (grpc or http)",
+			Value: DefaultOTLPProtocol,
+		},
+		&cli.BoolFlag{
+			Name:  OTLPInsecureFlag,
+			Usage: "push the metrics to the otlp endpoint without tls",
+		},
+		&cli.DurationFlag{
+			Name:  OTLPIntervalFlag,
+			Usage: "interval between otlp metric pushes",
+			Value: DefaultOTLPInterval,
+		},
+		&cli.StringSliceFlag{
+			Name:  OTLPHeaderFlag,
+			Usage: "header sent with the otlp requests as key=value",
+		},
+		&cli.StringSliceFlag{
+			Name:  OTLPAttributeFlag,
+			Usage: "resource attribute of the otlp metrics as key=value",
+		},
+		&cli.StringFlag{
+			Name:  PushURLFlag,
+			Usage: "url of a pushgateway or prometheus remote write endpoint the metrics are pushed to, disabled if not set",
+		},
+		&cli.StringFlag{
+			Name:  PushModeFlag,
+			Usage: "protocol of the push url (remote-write or pushgateway)",
+			Value: DefaultPushMode,
+		},
+		&cli.DurationFlag{
+			Name:  PushIntervalFlag,
+			Usage: "interval between metric pushes",
+			Value: DefaultPushInterval,
+		},
+		&cli.StringFlag{
+			Name:  PushJobFlag,
+			Usage: "job label of the pushed metrics",
+			Value: DefaultPushJob,
+		},
+		&cli.StringFlag{
+			Name:  PushInstanceFlag,
+			Usage: "instance label of the pushed metrics, defaults to the hostname",
+		},
+		&cli.IntFlag{
+			Name:  PushBufferSizeFlag,
+			Usage: "number of remote write requests buffered while the endpoint is unreachable",
+			Value: DefaultPushBufferSize,
+		},
+		&cli.StringFlag{
+			Name:  PushUsernameFlag,
+			Usage: "username for basic authentication at the push url",
+		},
+		&cli.StringFlag{
+			Name:  PushPasswordFlag,
+			Usage: "password for basic authentication at the push url",
+		},
+		&cli.StringFlag{
+			Name:  PushBearerTokenFlag,
+			Usage: "bearer token sent to the push url",
+		},
+		&cli.IntFlag{
+			Name:  EventBufferSizeFlag,
+			Usage: "number of events buffered per event stream client before it is disconnected",
+			Value: DefaultEventBufferSize,
+		},
+		&cli.StringSliceFlag{
+			Name:  RateLimitFlag,
+			Usage: "rate limit of a route group (api, discovery, metrics or admin) as group=requests/period[:burst] or group=off",
+		},
+		&cli.IntFlag{
+			Name:  RateLimitMaxClientsFlag,
+			Usage: "maximum number of clients tracked per rate limited route group",
+			Value: DefaultRateLimitMaxClients,
+		},
+		&cli.StringSliceFlag{
+			Name:  TrustedProxiesFlag,
+			Usage: "addresses or cidr ranges of reverse proxies trusted to set the client ip",
+		},
+		&cli.StringFlag{
+			Name:  APIKeysFileFlag,
+			Usage: "json file of api keys, enables api key authentication if set",
+		},
+		&cli.StringSliceFlag{
+			Name:  MetricsAllowFlag,
+			Usage: "addresses or cidr ranges allowed to scrape metrics without an api key",
+		},
+		&cli.BoolFlag{
+			Name:  CompressionFlag,
+			Usage: "compress api responses with zstd or gzip if the client accepts it",
+			Value: true,
+		},
+		&cli.StringFlag{
+			Name:  GeoCentroidsFlag,
+			Usage: "csv file of country and region centroids (country,region,latitude,longitude) extending the bundled dataset",
+		},
+		&cli.StringSliceFlag{
+			Name:  WatchProviderFlag,
+			Usage: "id of a provider exported individually in the metrics, can be changed with the admin api",
+		},
+	}
+}
+
+func DeclareExportFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     ExportSourceFlag,
+			Usage:    "ndjson snapshot file, \"-\" for stdin, or url of a running propmon instance",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  ExportFormatFlag,
+			Usage: "export format (csv, ndjson or parquet)",
+			Value: "csv",
+		},
+		&cli.StringFlag{
+			Name:  ExportOutputFlag,
+			Usage: "output file, \"-\" for stdout",
+			Value: "-",
+		},
+		&cli.StringFlag{
+			Name:  ExportAPIKeyFlag,
+			Usage: "api key with the read:export scope, sent to a running propmon instance",
+		},
 	}
 }
 
-func SetConfig(ctx *cli.Context) {
+func SetConfig(ctx *cli.Context) error {
 	BrokerAddress = ctx.String(BrokerAddressFlag)
 	MetricsAddress = ctx.String(MetricsAddressFlag)
+	MetricsTLSCert = ctx.String(MetricsTLSCertFlag)
+	MetricsTLSKey = ctx.String(MetricsTLSKeyFlag)
+	APIAddress = ctx.String(APIAddressFlag)
+	APITLSCert = ctx.String(APITLSCertFlag)
+	APITLSKey = ctx.String(APITLSKeyFlag)
+	AdminAddress = ctx.String(AdminAddressFlag)
+	AdminTLSCert = ctx.String(AdminTLSCertFlag)
+	AdminTLSKey = ctx.String(AdminTLSKeyFlag)
+	AuditLog = ctx.String(AuditLogFlag)
+	GRPCAddress = ctx.String(GRPCAddressFlag)
+	GRPCTLSCert = ctx.String(GRPCTLSCertFlag)
+	GRPCTLSKey = ctx.String(GRPCTLSKeyFlag)
 	ProposalLifetime = ctx.Duration(ProposalLifetimeFlag)
 	ExpirationJobInterval = ctx.Duration(ExpirationJobIntervalFlag)
+	if ExpirationJobInterval <= 0 {
+		return fmt.Errorf("invalid expiration job delay %s: must be positive", ExpirationJobInterval)
+	}
 	QualityOracle = ctx.String(QualityOracleFlag)
 	QualityUpdateInterval = ctx.Duration(QualityUpdateIntervalFlag)
+	StatsCacheTTL = ctx.Duration(StatsCacheTTLFlag)
+	MetricsCacheTTL = ctx.Duration(MetricsCacheTTLFlag)
+	MetricsNetworkLimit = ctx.Int(MetricsNetworkLimitFlag)
+	AnalyticsInterval = ctx.Duration(AnalyticsIntervalFlag)
+	ConcentrationTop = ctx.Int(ConcentrationTopFlag)
+	if AnalyticsInterval <= 0 {
+		return fmt.Errorf("invalid analytics interval %s: must be positive", AnalyticsInterval)
+	}
+	if ConcentrationTop < 1 {
+		return fmt.Errorf("invalid concentration top %d: must be at least 1", ConcentrationTop)
+	}
+
+	OTLPEndpoint = ctx.String(OTLPEndpointFlag)
+	OTLPProtocol = ctx.String(OTLPProtocolFlag)
+	OTLPInsecure = ctx.Bool(OTLPInsecureFlag)
+	OTLPInterval = ctx.Duration(OTLPIntervalFlag)
+
+	PushURL = ctx.String(PushURLFlag)
+	PushMode = ctx.String(PushModeFlag)
+	PushInterval = ctx.Duration(PushIntervalFlag)
+	PushJob = ctx.String(PushJobFlag)
+	PushInstance = ctx.String(PushInstanceFlag)
+	PushBufferSize = ctx.Int(PushBufferSizeFlag)
+	PushUsername = ctx.String(PushUsernameFlag)
+	PushPassword = ctx.String(PushPasswordFlag)
+	PushBearerToken = ctx.String(PushBearerTokenFlag)
+
+	if PushMode != "remote-write" && PushMode != "pushgateway" {
+		return fmt.Errorf("invalid push mode %q: expected remote-write or pushgateway", PushMode)
+	}
+	if PushBufferSize < 1 {
+		return fmt.Errorf("invalid push buffer size %d: must be at least 1", PushBufferSize)
+	}
+	if PushInterval <= 0 {
+		return fmt.Errorf("invalid push interval %s: must be positive", PushInterval)
+	}
+	if PushBearerToken != "" && PushUsername != "" {
+		return errors.New("invalid push authentication: a bearer token and a username are mutually exclusive")
+	}
+
+	if OTLPProtocol != "grpc" && OTLPProtocol != "http" {
+		return fmt.Errorf("invalid otlp protocol %q: expected grpc or http", OTLPProtocol)
+	}
+
+	var err error
+	if OTLPHeaders, err = parseKeyValues(ctx.StringSlice(OTLPHeaderFlag)); err != nil {
+		return fmt.Errorf("invalid otlp header: %w", err)
+	}
+	if OTLPAttributes, err = parseKeyValues(ctx.StringSlice(OTLPAttributeFlag)); err != nil {
+		return fmt.Errorf("invalid otlp resource attribute: %w", err)
+	}
+
+	ChurnWindows = nil
+	for _, s := range ctx.StringSlice(ChurnWindowFlag) {
+		window, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid churn window %q: %w", s, err)
+		}
+		if window < time.Minute {
+			return fmt.Errorf("invalid churn window %q: shorter than a minute", s)
+		}
+		ChurnWindows = append(ChurnWindows, window)
+	}
+	EventBufferSize = ctx.Int(EventBufferSizeFlag)
+	RateLimitMaxClients = ctx.Int(RateLimitMaxClientsFlag)
+	TrustedProxies = ctx.StringSlice(TrustedProxiesFlag)
+	APIKeysFile = ctx.String(APIKeysFileFlag)
+	MetricsAllow = ctx.StringSlice(MetricsAllowFlag)
+	Compression = ctx.Bool(CompressionFlag)
+	GeoCentroids = ctx.String(GeoCentroidsFlag)
+	WatchProviders = ctx.StringSlice(WatchProviderFlag)
+
+	if EventBufferSize < 1 {
+		return fmt.Errorf("invalid event buffer size %d: must be at least 1", EventBufferSize)
+	}
+	if RateLimitMaxClients < 1 {
+		return fmt.Errorf("invalid rate limit max clients %d: must be at least 1", RateLimitMaxClients)
+	}
+
+	RateLimits = make(map[string]RateLimit)
+	for _, s := range slices.Concat(DefaultRateLimits, ctx.StringSlice(RateLimitFlag)) {
+		group, limit, err := ParseRateLimit(s)
+		if err != nil {
+			return err
+		}
+		RateLimits[group] = limit
+	}
+
+	return nil
+}
+
+// parseKeyValues parses a list of key=value pairs.
+func parseKeyValues(pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%q: expected key=value", pair)
+		}
+		values[key] = value
+	}
+	return values, nil
 }
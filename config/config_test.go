@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// setConfig runs SetConfig with the flags parsed from the arguments.
+func setConfig(args ...string) error {
+	app := &cli.App{
+		Flags:  DeclareFlags(),
+		Action: SetConfig,
+	}
+	return app.Run(append([]string{"propmon"}, args...))
+}
+
+func TestSetConfigDefaults(t *testing.T) {
+	if err := setConfig(); err != nil {
+		t.Fatalf("SetConfig with default flags failed: %v", err)
+	}
+}
+
+func TestSetConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"zero rate limit clients", []string{"--rate-limit-max-clients", "0"}},
+		{"negative rate limit clients", []string{"--rate-limit-max-clients", "-1"}},
+		{"unknown rate limit group", []string{"--rate-limit", "ap1=10/1m"}},
+		{"zero expiration job delay", []string{"--expiration-job-delay", "0s"}},
+		{"negative expiration job delay", []string{"--expiration-job-delay", "-20s"}},
+		{"zero event buffer size", []string{"--event-buffer-size", "0"}},
+		{"negative event buffer size", []string{"--event-buffer-size", "-1"}},
+		{"zero analytics interval", []string{"--analytics-interval", "0s"}},
+		{"negative analytics interval", []string{"--analytics-interval", "-1m"}},
+		{"zero concentration top", []string{"--concentration-top", "0"}},
+		{"zero push interval", []string{"--push-interval", "0s"}},
+		{"negative push interval", []string{"--push-interval", "-30s"}},
+		{"push bearer token and username", []string{"--push-bearer-token", "token", "--push-username", "user"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := setConfig(tt.args...); err == nil {
+				t.Errorf("SetConfig(%q) succeeded, want an error", tt.args)
+			}
+		})
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		group   string
+		want    RateLimit
+		wantErr bool
+	}{
+		{"api=20/1m", "api", RateLimit{Requests: 20, Period: time.Minute, Burst: 20}, false},
+		{"discovery=5/1s:10", "discovery", RateLimit{Requests: 5, Period: time.Second, Burst: 10}, false},
+		{"metrics=off", "metrics", RateLimit{}, false},
+		{"admin=1/1h", "admin", RateLimit{Requests: 1, Period: time.Hour, Burst: 1}, false},
+		{"ap1=10/1m", "", RateLimit{}, true},
+		{"=10/1m", "", RateLimit{}, true},
+		{"api", "", RateLimit{}, true},
+		{"api=10", "", RateLimit{}, true},
+		{"api=0/1m", "", RateLimit{}, true},
+		{"api=x/1m", "", RateLimit{}, true},
+		{"api=10/0s", "", RateLimit{}, true},
+		{"api=10/minute", "", RateLimit{}, true},
+		{"api=10/1m:0", "", RateLimit{}, true},
+		{"api=10/1m:x", "", RateLimit{}, true},
+	}
+
+	for _, tt := range tests {
+		group, got, err := ParseRateLimit(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRateLimit(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if group != tt.group || got != tt.want {
+			t.Errorf("ParseRateLimit(%q) = %q, %+v, want %q, %+v", tt.in, group, got, tt.group, tt.want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+// number of rows buffered by the parquet writer before a row group is flushed
+const parquetRowGroupSize = 10000
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatNDJSON  Format = "ndjson"
+	FormatParquet Format = "parquet"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatCSV, FormatNDJSON, FormatParquet:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q", s)
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/vnd.apache.parquet"
+	}
+}
+
+// Writer streams proposals in an export format. Close must be called to flush the output.
+type Writer interface {
+	Write(p *proposal.Proposal) error
+	Close() error
+}
+
+// NewWriter creates a writer for the format. NDJSON keeps the proposals as they are,
+// CSV and parquet flatten them into the columns of Row.
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	case FormatParquet:
+		return &parquetWriter{writer: parquet.NewGenericWriter[Row](w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// Row is the flattened form of a proposal. Fields holding several values are joined with ";",
+// access policies as "id:source".
+type Row struct {
+	ProviderID     string   `parquet:"provider_id"`
+	ServiceType    string   `parquet:"service_type"`
+	Format         string   `parquet:"format"`
+	Compatibility  int64    `parquet:"compatibility"`
+	Continent      string   `parquet:"continent"`
+	Country        string   `parquet:"country"`
+	Region         string   `parquet:"region"`
+	City           string   `parquet:"city"`
+	Asn            int64    `parquet:"asn"`
+	Isp            string   `parquet:"isp"`
+	IpType         string   `parquet:"ip_type"`
+	Quality        *float64 `parquet:"quality,optional"`
+	Latency        *float64 `parquet:"latency,optional"`
+	Bandwidth      *float64 `parquet:"bandwidth,optional"`
+	Uptime         *float64 `parquet:"uptime,optional"`
+	RestrictedNode *bool    `parquet:"restricted_node,optional"`
+	AccessPolicies string   `parquet:"access_policies"`
+	ContactTypes   string   `parquet:"contact_types"`
+}
+
+func NewRow(p *proposal.Proposal) Row {
+	row := Row{
+		ProviderID:    p.ProviderID,
+		ServiceType:   p.ServiceType,
+		Format:        p.Format,
+		Compatibility: int64(p.Compatibility),
+		Continent:     p.Location.Continent,
+		Country:       p.Location.Country,
+		Region:        p.Location.Region,
+		City:          p.Location.City,
+		Asn:           int64(p.Location.Asn),
+		Isp:           p.Location.Isp,
+		IpType:        p.Location.IpType,
+	}
+
+	if q := p.Quality; q != nil {
+		row.Quality = &q.Quality
+		row.Latency = &q.Latency
+		row.Bandwidth = &q.Bandwidth
+		row.Uptime = &q.Uptime
+		row.RestrictedNode = &q.RestrictedNode
+	}
+
+	policies := make([]string, len(p.AccessPolicies))
+	for i, policy := range p.AccessPolicies {
+		policies[i] = policy.ID + ":" + policy.Source
+	}
+	row.AccessPolicies = strings.Join(policies, ";")
+
+	contacts := make([]string, len(p.Contacts))
+	for i, contact := range p.Contacts {
+		contacts[i] = contact.Type
+	}
+	row.ContactTypes = strings.Join(contacts, ";")
+
+	return row
+}
+
+var csvHeader = []string{
+	"provider_id", "service_type", "format", "compatibility", "continent", "country", "region", "city", "asn",
+	"isp", "ip_type", "quality", "latency", "bandwidth", "uptime", "restricted_node", "access_policies",
+	"contact_types",
+}
+
+func (r Row) csvRecord() []string {
+	return []string{
+		r.ProviderID, r.ServiceType, r.Format, strconv.FormatInt(r.Compatibility, 10), r.Continent, r.Country,
+		r.Region, r.City, strconv.FormatInt(r.Asn, 10), r.Isp, r.IpType, formatFloat(r.Quality),
+		formatFloat(r.Latency), formatFloat(r.Bandwidth), formatFloat(r.Uptime), formatBool(r.RestrictedNode),
+		r.AccessPolicies, r.ContactTypes,
+	}
+}
+
+func formatFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
+
+func formatBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{writer: csv.NewWriter(w)}
+}
+
+func (w *csvWriter) Write(p *proposal.Proposal) error {
+	if !w.headerWritten {
+		if err := w.writer.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	return w.writer.Write(NewRow(p).csvRecord())
+}
+
+func (w *csvWriter) Close() error {
+	if !w.headerWritten {
+		if err := w.writer.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(p *proposal.Proposal) error {
+	return w.encoder.Encode(p)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+type parquetWriter struct {
+	writer   *parquet.GenericWriter[Row]
+	buffered int
+}
+
+func (w *parquetWriter) Write(p *proposal.Proposal) error {
+	if _, err := w.writer.Write([]Row{NewRow(p)}); err != nil {
+		return err
+	}
+
+	w.buffered++
+	if w.buffered >= parquetRowGroupSize {
+		w.buffered = 0
+		return w.writer.Flush()
+	}
+
+	return nil
+}
+
+func (w *parquetWriter) Close() error {
+	return w.writer.Close()
+}
@@ -0,0 +1,199 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func testProposals() []*proposal.Proposal {
+	return []*proposal.Proposal{
+		{
+			Format:         "service-proposal/v3",
+			Compatibility:  2,
+			ProviderID:     "0xaaa",
+			ServiceType:    "wireguard",
+			Location:       proposal.Location{Continent: "EU", Country: "DE", City: "Berlin", Asn: 3320, Isp: "Example, Inc.", IpType: "residential"},
+			Contacts:       []proposal.Contact{{Type: "nats/p2p/v1"}, {Type: "broker"}},
+			Quality:        &proposal.Quality{Quality: 2.5, Latency: 40, Bandwidth: 80, Uptime: 20},
+			AccessPolicies: []proposal.AccessPolicy{{ID: "mysterium", Source: "https://trust.mysterium.network"}},
+		},
+		{
+			Format:      "service-proposal/v3",
+			ProviderID:  "0xbbb",
+			ServiceType: "openvpn",
+			Location:    proposal.Location{Country: "US"},
+		},
+	}
+}
+
+func write(t *testing.T, format Format, proposals []*proposal.Proposal) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range proposals {
+		if err := w.Write(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in          string
+		want        Format
+		contentType string
+		wantErr     bool
+	}{
+		{"csv", FormatCSV, "text/csv", false},
+		{"ndjson", FormatNDJSON, "application/x-ndjson", false},
+		{"parquet", FormatParquet, "application/vnd.apache.parquet", false},
+		{"json", "", "", true},
+		{"", "", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFormat(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if !tt.wantErr && got.ContentType() != tt.contentType {
+			t.Errorf("%s content type = %q, want %q", got, got.ContentType(), tt.contentType)
+		}
+	}
+}
+
+func TestCSV(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(write(t, FormatCSV, testProposals()))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		csvHeader,
+		{"0xaaa", "wireguard", "service-proposal/v3", "2", "EU", "DE", "", "Berlin", "3320", "Example, Inc.", "residential",
+			"2.5", "40", "80", "20", "false", "mysterium:https://trust.mysterium.network", "nats/p2p/v1;broker"},
+		{"0xbbb", "openvpn", "service-proposal/v3", "0", "", "US", "", "", "0", "", "", "", "", "", "", "", "", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+}
+
+func TestCSVEmpty(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(write(t, FormatCSV, nil))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || !reflect.DeepEqual(records[0], csvHeader) {
+		t.Errorf("records = %q, want only the header", records)
+	}
+}
+
+func TestNDJSONSnapshotRoundTrip(t *testing.T) {
+	var got []*proposal.Proposal
+	err := ReadSnapshot(bytes.NewReader(write(t, FormatNDJSON, testProposals())), func(p *proposal.Proposal) error {
+		got = append(got, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, testProposals()) {
+		t.Errorf("snapshot = %+v, want %+v", got, testProposals())
+	}
+}
+
+func TestParquet(t *testing.T) {
+	data := write(t, FormatParquet, testProposals())
+
+	rows, err := parquet.Read[Row](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := make([]Row, 0, 2)
+	for _, p := range testProposals() {
+		want = append(want, NewRow(p))
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "snapshot.ndjson")
+	if err := os.WriteFile(source, write(t, FormatNDJSON, testProposals()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Convert(source, "", FormatCSV, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), write(t, FormatCSV, testProposals())) {
+		t.Errorf("converted csv = %s", buf.String())
+	}
+}
+
+func TestConvertInstance(t *testing.T) {
+	snapshot := write(t, FormatNDJSON, testProposals())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/export" || r.URL.Query().Get("format") != "ndjson" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer export-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write(snapshot)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		apiKey  string
+		wantErr bool
+	}{
+		{"api key", "export-key", false},
+		{"missing api key", "", true},
+		{"wrong api key", "other-key", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Convert(server.URL, tt.apiKey, FormatCSV, &buf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(buf.Bytes(), write(t, FormatCSV, testProposals())) {
+				t.Errorf("converted csv = %s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+// ReadSnapshot decodes proposals from an NDJSON snapshot, as written by the ndjson export format.
+func ReadSnapshot(r io.Reader, fn func(p *proposal.Proposal) error) error {
+	decoder := json.NewDecoder(r)
+
+	for {
+		var p proposal.Proposal
+		if err := decoder.Decode(&p); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to decode snapshot: %w", err)
+		}
+
+		if err := fn(&p); err != nil {
+			return err
+		}
+	}
+}
+
+// OpenSource opens a persisted NDJSON snapshot file, or the ndjson export of a running propmon
+// instance if the source is an http(s) url. The api key is only sent to instances and may be empty.
+func OpenSource(source string, apiKey string) (io.ReadCloser, error) {
+	if source == "-" {
+		return os.Stdin, nil
+	}
+
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshot: %w", err)
+		}
+		return file, nil
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source url: %w", err)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/api/v1/export"
+	}
+	query := u.Query()
+	query.Set("format", string(FormatNDJSON))
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source url: %w", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("export request failed: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("export request failed: %s", res.Status)
+	}
+
+	return res.Body, nil
+}
+
+// Convert writes all proposals of the source in the given format.
+func Convert(source string, apiKey string, format Format, w io.Writer) error {
+	src, err := OpenSource(source, apiKey)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	writer, err := NewWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	if err := ReadSnapshot(src, writer.Write); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
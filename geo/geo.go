@@ -0,0 +1,120 @@
+package geo
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+// centroids contains the centroids of countries and their regions as country,region,latitude,longitude.
+// Country centroids have an empty region.
+//
+//go:embed centroids.csv
+var centroids string
+
+type Precision string
+
+const (
+	PrecisionRegion  Precision = "region"
+	PrecisionCountry Precision = "country"
+)
+
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Locator maps locations to coordinates without any external lookups.
+type Locator struct {
+	countries map[string]Point
+	// regions are keyed by country code and lower case region name
+	regions map[string]Point
+}
+
+// NewLocator creates a locator from the bundled centroid dataset.
+func NewLocator() (*Locator, error) {
+	l := &Locator{
+		countries: make(map[string]Point),
+		regions:   make(map[string]Point),
+	}
+
+	if err := l.read(strings.NewReader(centroids)); err != nil {
+		return nil, fmt.Errorf("invalid bundled centroids: %w", err)
+	}
+
+	return l, nil
+}
+
+// Load adds the centroids of a csv file in the format of the bundled dataset. Its entries replace
+// bundled entries of the same country and region.
+func (l *Locator) Load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open centroids: %w", err)
+	}
+	defer f.Close()
+
+	if err := l.read(f); err != nil {
+		return fmt.Errorf("invalid centroids %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (l *Locator) read(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+
+	header := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header {
+			header = false
+			continue
+		}
+
+		lat, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return fmt.Errorf("invalid latitude %q", record[2])
+		}
+		lon, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			return fmt.Errorf("invalid longitude %q", record[3])
+		}
+
+		point := Point{Latitude: lat, Longitude: lon}
+		if record[1] == "" {
+			l.countries[strings.ToUpper(record[0])] = point
+		} else {
+			l.regions[regionKey(record[0], record[1])] = point
+		}
+	}
+}
+
+func regionKey(country string, region string) string {
+	return strings.ToUpper(country) + "/" + strings.ToLower(strings.TrimSpace(region))
+}
+
+// Locate returns the centroid of the region of the location, or of its country if the region is unknown.
+func (l *Locator) Locate(location proposal.Location) (Point, Precision, bool) {
+	if location.Region != "" {
+		if point, ok := l.regions[regionKey(location.Country, location.Region)]; ok {
+			return point, PrecisionRegion, true
+		}
+	}
+
+	point, ok := l.countries[strings.ToUpper(location.Country)]
+	return point, PrecisionCountry, ok
+}
@@ -0,0 +1,110 @@
+package geo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func newTestLocator(t *testing.T) *Locator {
+	t.Helper()
+
+	l, err := NewLocator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func writeCentroids(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "centroids.csv")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLocate(t *testing.T) {
+	l := newTestLocator(t)
+
+	tests := []struct {
+		name      string
+		location  proposal.Location
+		want      Point
+		precision Precision
+		ok        bool
+	}{
+		{"region", proposal.Location{Country: "DE", Region: "Saarland"}, Point{49.3964, 7.023}, PrecisionRegion, true},
+		{"region case-insensitive", proposal.Location{Country: "de", Region: " saarland "}, Point{49.3964, 7.023}, PrecisionRegion, true},
+		{"unknown region", proposal.Location{Country: "DE", Region: "Atlantis"}, Point{51.2025, 10.3822}, PrecisionCountry, true},
+		{"country", proposal.Location{Country: "DE"}, Point{51.2025, 10.3822}, PrecisionCountry, true},
+		{"region of other country", proposal.Location{Country: "AD", Region: "Saarland"}, Point{42.5507, 1.5762}, PrecisionCountry, true},
+		{"unknown country", proposal.Location{Country: "XX"}, Point{}, PrecisionCountry, false},
+		{"empty", proposal.Location{}, Point{}, PrecisionCountry, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			point, precision, ok := l.Locate(tt.location)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if point != tt.want || precision != tt.precision {
+				t.Errorf("Locate = %v %s, want %v %s", point, precision, tt.want, tt.precision)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	l := newTestLocator(t)
+
+	path := writeCentroids(t, "country,region,latitude,longitude\nDE,,1,2\nXX,,3,4\nXX,Somewhere,5,6\n")
+	if err := l.Load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		location proposal.Location
+		want     Point
+	}{
+		{proposal.Location{Country: "DE"}, Point{1, 2}},
+		{proposal.Location{Country: "XX"}, Point{3, 4}},
+		{proposal.Location{Country: "XX", Region: "somewhere"}, Point{5, 6}},
+		// bundled entries that are not replaced are kept
+		{proposal.Location{Country: "DE", Region: "Saarland"}, Point{49.3964, 7.023}},
+	}
+
+	for _, tt := range tests {
+		if point, _, ok := l.Locate(tt.location); !ok || point != tt.want {
+			t.Errorf("Locate(%+v) = %v %v, want %v", tt.location, point, ok, tt.want)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid latitude", "country,region,latitude,longitude\nDE,,north,2\n"},
+		{"invalid longitude", "country,region,latitude,longitude\nDE,,1,east\n"},
+		{"missing field", "country,region,latitude,longitude\nDE,,1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := newTestLocator(t).Load(writeCentroids(t, tt.content)); err == nil {
+				t.Error("Load succeeded, want an error")
+			}
+		})
+	}
+
+	if err := newTestLocator(t).Load(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("Load of a missing file succeeded, want an error")
+	}
+}
@@ -0,0 +1,134 @@
+package geo
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	Features []*Feature `json:"features"`
+}
+
+type Feature struct {
+	Type       string         `json:"type"`
+	Geometry   Geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Geometry is a GeoJSON point, its coordinates are longitude and latitude.
+type Geometry struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+func newFeatureCollection() *FeatureCollection {
+	return &FeatureCollection{Type: "FeatureCollection", Features: []*Feature{}}
+}
+
+func newFeature(point Point, properties map[string]any) *Feature {
+	return &Feature{
+		Type:       "Feature",
+		Geometry:   Geometry{Type: "Point", Coordinates: [2]float64{point.Longitude, point.Latitude}},
+		Properties: properties,
+	}
+}
+
+// Providers returns a point feature for every provider that can be located.
+func (l *Locator) Providers(providers []*proposal.Provider) *FeatureCollection {
+	fc := newFeatureCollection()
+
+	for _, p := range providers {
+		point, precision, ok := l.Locate(p.Location)
+		if !ok {
+			continue
+		}
+
+		services := make([]string, 0, len(p.Services))
+		for _, s := range p.Services {
+			services = append(services, s.ServiceType)
+		}
+
+		properties := map[string]any{
+			"id":        p.ID,
+			"continent": p.Location.Continent,
+			"country":   p.Location.Country,
+			"region":    p.Location.Region,
+			"city":      p.Location.City,
+			"asn":       p.Location.Asn,
+			"isp":       p.Location.Isp,
+			"ip_type":   p.Location.IpType,
+			"services":  services,
+			"precision": precision,
+		}
+		if p.Quality != nil {
+			properties["quality"] = p.Quality.Quality
+			properties["latency"] = p.Quality.Latency
+			properties["bandwidth"] = p.Quality.Bandwidth
+			properties["uptime"] = p.Quality.Uptime
+		}
+
+		fc.Features = append(fc.Features, newFeature(point, properties))
+	}
+
+	return fc
+}
+
+type countryAggregate struct {
+	providers   int
+	proposals   int
+	withQuality int
+	quality     float64
+}
+
+// Countries aggregates the providers per country into a feature at the centroid of the country.
+// The country property holds the ISO 3166-1 alpha-2 code, to join the features with country shapes.
+func (l *Locator) Countries(providers []*proposal.Provider) *FeatureCollection {
+	aggregates := make(map[string]*countryAggregate)
+
+	for _, p := range providers {
+		country := strings.ToUpper(p.Location.Country)
+		a, ok := aggregates[country]
+		if !ok {
+			a = &countryAggregate{}
+			aggregates[country] = a
+		}
+
+		a.providers++
+		a.proposals += len(p.Services)
+		if p.Quality != nil {
+			a.withQuality++
+			a.quality += p.Quality.Quality
+		}
+	}
+
+	countries := make([]string, 0, len(aggregates))
+	for country := range aggregates {
+		countries = append(countries, country)
+	}
+	slices.Sort(countries)
+
+	fc := newFeatureCollection()
+	for _, country := range countries {
+		point, ok := l.countries[country]
+		if !ok {
+			continue
+		}
+
+		a := aggregates[country]
+		properties := map[string]any{
+			"country":   country,
+			"providers": a.providers,
+			"proposals": a.proposals,
+		}
+		if a.withQuality > 0 {
+			properties["avg_quality"] = a.quality / float64(a.withQuality)
+		}
+
+		fc.Features = append(fc.Features, newFeature(point, properties))
+	}
+
+	return fc
+}
@@ -0,0 +1,116 @@
+package geo
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func testProviders() []*proposal.Provider {
+	return []*proposal.Provider{
+		{
+			ID:       "0xaaa",
+			Location: proposal.Location{Country: "DE", Region: "Saarland"},
+			Quality:  &proposal.Quality{Quality: 3},
+			Services: []proposal.Service{{ServiceType: "wireguard"}, {ServiceType: "openvpn"}},
+		},
+		{
+			ID:       "0xbbb",
+			Location: proposal.Location{Country: "de"},
+			Quality:  &proposal.Quality{Quality: 1},
+			Services: []proposal.Service{{ServiceType: "wireguard"}},
+		},
+		{
+			ID:       "0xccc",
+			Location: proposal.Location{Country: "AD"},
+			Services: []proposal.Service{{ServiceType: "wireguard"}},
+		},
+		{
+			ID:       "0xddd",
+			Location: proposal.Location{Country: "XX"},
+			Services: []proposal.Service{{ServiceType: "wireguard"}},
+		},
+	}
+}
+
+func TestProviders(t *testing.T) {
+	fc := newTestLocator(t).Providers(testProviders())
+
+	if fc.Type != "FeatureCollection" {
+		t.Errorf("type = %q", fc.Type)
+	}
+	// providers without a known country are left out
+	if len(fc.Features) != 3 {
+		t.Fatalf("got %d features, want 3", len(fc.Features))
+	}
+
+	tests := []struct {
+		id          string
+		coordinates [2]float64
+		precision   Precision
+		services    []string
+		quality     any
+	}{
+		{"0xaaa", [2]float64{7.023, 49.3964}, PrecisionRegion, []string{"wireguard", "openvpn"}, 3.0},
+		{"0xbbb", [2]float64{10.3822, 51.2025}, PrecisionCountry, []string{"wireguard"}, 1.0},
+		{"0xccc", [2]float64{1.5762, 42.5507}, PrecisionCountry, []string{"wireguard"}, nil},
+	}
+
+	for i, tt := range tests {
+		f := fc.Features[i]
+		if f.Type != "Feature" || f.Geometry.Type != "Point" {
+			t.Errorf("%s: feature type %q, geometry type %q", tt.id, f.Type, f.Geometry.Type)
+		}
+		if f.Properties["id"] != tt.id {
+			t.Fatalf("feature %d id = %v, want %s", i, f.Properties["id"], tt.id)
+		}
+		if f.Geometry.Coordinates != tt.coordinates {
+			t.Errorf("%s: coordinates = %v, want %v", tt.id, f.Geometry.Coordinates, tt.coordinates)
+		}
+		if f.Properties["precision"] != tt.precision {
+			t.Errorf("%s: precision = %v, want %s", tt.id, f.Properties["precision"], tt.precision)
+		}
+		if services, _ := f.Properties["services"].([]string); !slices.Equal(services, tt.services) {
+			t.Errorf("%s: services = %v, want %v", tt.id, services, tt.services)
+		}
+		if quality, ok := f.Properties["quality"]; quality != tt.quality || ok != (tt.quality != nil) {
+			t.Errorf("%s: quality = %v, want %v", tt.id, quality, tt.quality)
+		}
+	}
+}
+
+func TestCountries(t *testing.T) {
+	fc := newTestLocator(t).Countries(testProviders())
+
+	tests := []struct {
+		country    string
+		providers  int
+		proposals  int
+		avgQuality any
+	}{
+		{"AD", 1, 1, nil},
+		{"DE", 2, 3, 2.0},
+	}
+
+	if len(fc.Features) != len(tests) {
+		t.Fatalf("got %d features, want %d", len(fc.Features), len(tests))
+	}
+
+	for i, tt := range tests {
+		p := fc.Features[i].Properties
+		if p["country"] != tt.country || p["providers"] != tt.providers || p["proposals"] != tt.proposals {
+			t.Errorf("feature %d = %v, want %s with %d providers and %d proposals", i, p, tt.country, tt.providers, tt.proposals)
+		}
+		if avg, ok := p["avg_quality"]; avg != tt.avgQuality || ok != (tt.avgQuality != nil) {
+			t.Errorf("%s: avg_quality = %v, want %v", tt.country, avg, tt.avgQuality)
+		}
+	}
+}
+
+func TestCountriesEmpty(t *testing.T) {
+	fc := newTestLocator(t).Countries(nil)
+	if fc.Features == nil || len(fc.Features) != 0 {
+		t.Errorf("features = %v, want an empty list", fc.Features)
+	}
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sch8ill/propmon/analytics"
+)
+
+var (
+	hhiDesc = prometheus.NewDesc("propmon_concentration_hhi", "Herfindahl-Hirschman index of the providers per dimension between 0 and 1",
+		[]string{"dimension"}, nil)
+	nakamotoDesc = prometheus.NewDesc("propmon_concentration_nakamoto", "Smallest number of groups with more than half of the providers",
+		[]string{"dimension"}, nil)
+	topShareDesc = prometheus.NewDesc("propmon_concentration_top_share", "Share of the providers in the largest groups",
+		[]string{"dimension", "top"}, nil)
+	concentrationGroupsDesc = prometheus.NewDesc("propmon_concentration_groups", "Number of distinct values of the dimension",
+		[]string{"dimension"}, nil)
+)
+
+type analyticsCollector struct {
+	service *analytics.Service
+}
+
+// RegisterAnalytics exports the last concentration report of the analytics service.
+func RegisterAnalytics(service *analytics.Service) {
+	Registry.MustRegister(&analyticsCollector{service: service})
+}
+
+func (c *analyticsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- hhiDesc
+	ch <- nakamotoDesc
+	ch <- topShareDesc
+	ch <- concentrationGroupsDesc
+}
+
+func (c *analyticsCollector) Collect(ch chan<- prometheus.Metric) {
+	report := c.service.Report()
+	if report == nil {
+		return
+	}
+
+	top := strconv.Itoa(c.service.Top())
+	dimensions := []struct {
+		name          string
+		concentration analytics.Concentration
+	}{
+		{"asn", report.Asn},
+		{"isp", report.Isp},
+		{"country", report.Country},
+	}
+	for _, d := range dimensions {
+		ch <- prometheus.MustNewConstMetric(hhiDesc, prometheus.GaugeValue, d.concentration.HHI, d.name)
+		ch <- prometheus.MustNewConstMetric(nakamotoDesc, prometheus.GaugeValue, float64(d.concentration.Nakamoto), d.name)
+		ch <- prometheus.MustNewConstMetric(topShareDesc, prometheus.GaugeValue, d.concentration.TopShare, d.name, top)
+		ch <- prometheus.MustNewConstMetric(concentrationGroupsDesc, prometheus.GaugeValue, float64(d.concentration.Groups), d.name)
+	}
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+var (
+	churnArrivalsDesc = prometheus.NewDesc("propmon_churn_arrivals", "New Service Proposals per country and service type",
+		[]string{"country", "service_type"}, nil)
+	churnUnregistrationsDesc = prometheus.NewDesc("propmon_churn_unregistrations", "Unregistered Service Proposals per country and service type",
+		[]string{"country", "service_type"}, nil)
+	churnExpiriesDesc = prometheus.NewDesc("propmon_churn_expiries", "Expired Service Proposals per country and service type",
+		[]string{"country", "service_type"}, nil)
+	churnEvictionsDesc = prometheus.NewDesc("propmon_churn_evictions", "Service Proposals evicted by the admin API per country and service type",
+		[]string{"country", "service_type"}, nil)
+	churnRateDesc = prometheus.NewDesc("propmon_churn_rate", "Share of the Service Proposals at the start of the window that disappeared within it",
+		[]string{"country", "service_type", "window"}, nil)
+	churnNetChangeDesc = prometheus.NewDesc("propmon_churn_net_change", "Arrivals minus departures of Service Proposals within the window",
+		[]string{"country", "service_type", "window"}, nil)
+)
+
+type churnWindow struct {
+	window time.Duration
+	churn  map[proposal.ChurnKey]proposal.Churn
+}
+
+func describeChurn(ch chan<- *prometheus.Desc) {
+	ch <- churnArrivalsDesc
+	ch <- churnUnregistrationsDesc
+	ch <- churnExpiriesDesc
+	ch <- churnEvictionsDesc
+	ch <- churnRateDesc
+	ch <- churnNetChangeDesc
+}
+
+func collectChurn(ch chan<- prometheus.Metric, s *snapshot) {
+	for key, c := range s.churn {
+		ch <- prometheus.MustNewConstMetric(churnArrivalsDesc, prometheus.CounterValue, float64(c.Arrivals), key.Country, key.ServiceType)
+		ch <- prometheus.MustNewConstMetric(churnUnregistrationsDesc, prometheus.CounterValue, float64(c.Unregistrations), key.Country, key.ServiceType)
+		ch <- prometheus.MustNewConstMetric(churnExpiriesDesc, prometheus.CounterValue, float64(c.Expiries), key.Country, key.ServiceType)
+		ch <- prometheus.MustNewConstMetric(churnEvictionsDesc, prometheus.CounterValue, float64(c.Evictions), key.Country, key.ServiceType)
+	}
+
+	for _, w := range s.churnWindows {
+		window := model.Duration(w.window).String()
+		for key, c := range w.churn {
+			departures := float64(c.Departures())
+			net := float64(c.Arrivals) - departures
+			ch <- prometheus.MustNewConstMetric(churnNetChangeDesc, prometheus.GaugeValue, net, key.Country, key.ServiceType, window)
+
+			// the proposals at the start of the window are the current ones without the net change
+			start := float64(s.churnPopulation[key]) - net
+			if start > 0 {
+				ch <- prometheus.MustNewConstMetric(churnRateDesc, prometheus.GaugeValue, departures/start, key.Country, key.ServiceType, window)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gather collects c on a pedantic registry and returns the samples keyed by name and labels,
+// e.g. `propmon_provider_count{country="DE",node_type="residential"}`. Histograms map to their sample count.
+func gather(t *testing.T, c prometheus.Collector) map[string]float64 {
+	t.Helper()
+
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(c); err != nil {
+		t.Fatal(err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	samples := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			labels := make([]string, 0, len(m.GetLabel()))
+			for _, l := range m.GetLabel() {
+				labels = append(labels, l.GetName()+`="`+l.GetValue()+`"`)
+			}
+
+			key := family.GetName()
+			if len(labels) > 0 {
+				key += "{" + strings.Join(labels, ",") + "}"
+			}
+
+			switch {
+			case m.Gauge != nil:
+				samples[key] = m.GetGauge().GetValue()
+			case m.Counter != nil:
+				samples[key] = m.GetCounter().GetValue()
+			case m.Histogram != nil:
+				samples[key] = float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+
+	return samples
+}
+
+// withPrefix returns the samples of the metric name.
+func withPrefix(samples map[string]float64, name string) map[string]float64 {
+	matching := make(map[string]float64)
+	for key, v := range samples {
+		if key == name || strings.HasPrefix(key, name+"{") {
+			matching[key] = v
+		}
+	}
+	return matching
+}
@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+// otherNetwork is the label value the networks beyond the top n are folded into.
+const otherNetwork = "other"
+
+var (
+	asnProviderCountDesc = prometheus.NewDesc("propmon_asn_provider_count", "Provider count per ASN",
+		[]string{"asn"}, nil)
+	asnQualityDesc = prometheus.NewDesc("propmon_asn_quality", "Average quality score per ASN",
+		[]string{"asn"}, nil)
+	ispProviderCountDesc = prometheus.NewDesc("propmon_isp_provider_count", "Provider count per ISP",
+		[]string{"isp"}, nil)
+	ispQualityDesc = prometheus.NewDesc("propmon_isp_quality", "Average quality score per ISP",
+		[]string{"isp"}, nil)
+	labelsFoldedDesc = prometheus.NewDesc("propmon_labels_folded", "Number of label values folded into the other bucket",
+		[]string{"label"}, nil)
+)
+
+type networkStats struct {
+	providers int
+	// rated is the number of providers with quality data
+	rated   int
+	quality float64
+}
+
+func (n *networkStats) add(q *proposal.Quality) {
+	n.providers++
+	if q != nil {
+		n.rated++
+		n.quality += q.Quality
+	}
+}
+
+func (n *networkStats) merge(o *networkStats) {
+	n.providers += o.providers
+	n.rated += o.rated
+	n.quality += o.quality
+}
+
+// networks counts the providers per network, e.g. per ASN or ISP.
+type networks map[string]*networkStats
+
+func (n networks) add(network string, q *proposal.Quality) {
+	if n[network] == nil {
+		n[network] = &networkStats{}
+	}
+	n[network].add(q)
+}
+
+// top keeps the limit networks with the most providers and folds the rest into the other bucket.
+// It returns the kept networks and the number of folded label values. A limit of 0 keeps all networks.
+func (n networks) top(limit int) (networks, int) {
+	if limit <= 0 || len(n) <= limit {
+		return n, 0
+	}
+
+	names := make([]string, 0, len(n))
+	for name := range n {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Or(cmp.Compare(n[b].providers, n[a].providers), cmp.Compare(a, b))
+	})
+
+	kept := make(networks, limit+1)
+	other := &networkStats{}
+	for i, name := range names {
+		if i < limit && name != otherNetwork {
+			kept[name] = n[name]
+			continue
+		}
+		other.merge(n[name])
+	}
+	kept[otherNetwork] = other
+
+	return kept, len(names) - len(kept) + 1
+}
+
+func collectNetworks(ch chan<- prometheus.Metric, label string, n networks, limit int, countDesc, qualityDesc *prometheus.Desc) {
+	kept, folded := n.top(limit)
+	ch <- prometheus.MustNewConstMetric(labelsFoldedDesc, prometheus.GaugeValue, float64(folded), label)
+
+	for network, stats := range kept {
+		ch <- prometheus.MustNewConstMetric(countDesc, prometheus.GaugeValue, float64(stats.providers), network)
+		if stats.rated > 0 {
+			ch <- prometheus.MustNewConstMetric(qualityDesc, prometheus.GaugeValue, stats.quality/float64(stats.rated), network)
+		}
+	}
+}
+
+// topCounts keeps the limit label values with the highest counts and folds the rest into other, like networks.top.
+func topCounts[K comparable](counts map[K]int, limit int, other K, compare func(a, b K) int) (map[K]int, int) {
+	if limit <= 0 || len(counts) <= limit {
+		return counts, 0
+	}
+
+	keys := make([]K, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b K) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), compare(a, b))
+	})
+
+	kept := make(map[K]int, limit+1)
+	for i, key := range keys {
+		if i < limit && key != other {
+			kept[key] = counts[key]
+			continue
+		}
+		kept[other] += counts[key]
+	}
+
+	return kept, len(keys) - len(kept) + 1
+}
+
+func asnLabel(asn int) string {
+	return strconv.Itoa(asn)
+}
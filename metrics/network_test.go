@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func testNetworks(counts map[string]int) networks {
+	n := make(networks)
+	for name, count := range counts {
+		for range count {
+			n.add(name, nil)
+		}
+	}
+	return n
+}
+
+func TestNetworksTop(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[string]int
+		limit  int
+		want   map[string]int
+		folded int
+	}{
+		{"no limit", map[string]int{"a": 1, "b": 2}, 0, map[string]int{"a": 1, "b": 2}, 0},
+		{"within limit", map[string]int{"a": 1, "b": 2}, 2, map[string]int{"a": 1, "b": 2}, 0},
+		{"folds smallest", map[string]int{"a": 1, "b": 3, "c": 2}, 2, map[string]int{"b": 3, "c": 2, "other": 1}, 1},
+		{"ties by name", map[string]int{"a": 1, "b": 1, "c": 1}, 1, map[string]int{"a": 1, "other": 2}, 2},
+		{"network named other", map[string]int{"other": 5, "a": 1, "b": 1}, 2, map[string]int{"a": 1, "other": 6}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, folded := testNetworks(tt.counts).top(tt.limit)
+
+			got := make(map[string]int, len(kept))
+			for name, stats := range kept {
+				got[name] = stats.providers
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("kept = %v, want %v", got, tt.want)
+			}
+			if folded != tt.folded {
+				t.Errorf("folded = %d, want %d", folded, tt.folded)
+			}
+		})
+	}
+}
+
+func TestNetworksQuality(t *testing.T) {
+	n := make(networks)
+	n.add("a", &proposal.Quality{Quality: 3})
+	n.add("a", &proposal.Quality{Quality: 1})
+	n.add("a", nil)
+	n.add("b", nil)
+	n.add("c", &proposal.Quality{Quality: 1})
+
+	samples := gather(t, &repositoryCollector{
+		repository: newTestRepository(),
+		snapshot:   &snapshot{asns: n, isps: make(networks)},
+		taken:      time.Now(),
+		options:    RepositoryOptions{CacheTTL: time.Hour, NetworkLimit: 1},
+	})
+
+	want := map[string]float64{
+		`propmon_asn_provider_count{asn="a"}`:     3,
+		`propmon_asn_provider_count{asn="other"}`: 2,
+		// the average only covers providers with quality data
+		`propmon_asn_quality{asn="a"}`:     2,
+		`propmon_asn_quality{asn="other"}`: 1,
+	}
+	got := withPrefix(samples, "propmon_asn_provider_count")
+	maps.Copy(got, withPrefix(samples, "propmon_asn_quality"))
+	if !maps.Equal(got, want) {
+		t.Errorf("samples = %v, want %v", got, want)
+	}
+
+	folded := withPrefix(samples, "propmon_labels_folded")
+	if folded[`propmon_labels_folded{label="asn"}`] != 2 || folded[`propmon_labels_folded{label="isp"}`] != 0 {
+		t.Errorf("folded = %v", folded)
+	}
+}
+
+func TestNetworksWithoutQuality(t *testing.T) {
+	samples := gather(t, &repositoryCollector{repository: newTestRepository(&proposal.Proposal{
+		ProviderID:  "0xaaa",
+		ServiceType: "wireguard",
+		Location:    proposal.Location{Asn: 3320, Isp: "DTAG"},
+	})})
+
+	if keys := slices.Collect(maps.Keys(withPrefix(samples, "propmon_isp_quality"))); len(keys) != 0 {
+		t.Errorf("isp quality exported without quality data: %v", keys)
+	}
+	if samples[`propmon_isp_provider_count{isp="DTAG"}`] != 1 {
+		t.Error("isp provider count missing")
+	}
+}
+
+func TestTopCounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[string]int
+		limit  int
+		want   map[string]int
+		folded int
+	}{
+		{"no limit", map[string]int{"a": 1, "b": 2}, 0, map[string]int{"a": 1, "b": 2}, 0},
+		{"within limit", map[string]int{"a": 1, "b": 2}, 2, map[string]int{"a": 1, "b": 2}, 0},
+		{"folds smallest", map[string]int{"a": 1, "b": 3, "c": 2}, 2, map[string]int{"b": 3, "c": 2, "other": 1}, 1},
+		{"ties by name", map[string]int{"a": 1, "b": 1, "c": 1}, 1, map[string]int{"a": 1, "other": 2}, 2},
+		{"value named other", map[string]int{"other": 5, "a": 1, "b": 1}, 2, map[string]int{"a": 1, "other": 6}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, folded := topCounts(tt.counts, tt.limit, otherNetwork, strings.Compare)
+			if !maps.Equal(kept, tt.want) {
+				t.Errorf("kept = %v, want %v", kept, tt.want)
+			}
+			if folded != tt.folded {
+				t.Errorf("folded = %d, want %d", folded, tt.folded)
+			}
+		})
+	}
+}
+
+func TestProposalLabelsFolded(t *testing.T) {
+	var proposals []*proposal.Proposal
+	for i, policy := range []string{"a", "a", "b", "c"} {
+		proposals = append(proposals, &proposal.Proposal{
+			ProviderID:     fmt.Sprintf("0x%d", i),
+			ServiceType:    "wireguard",
+			AccessPolicies: []proposal.AccessPolicy{{ID: policy, Source: "https://" + policy}},
+			Contacts:       []proposal.Contact{{Type: "contact/" + policy}},
+		})
+	}
+
+	samples := gather(t, &repositoryCollector{
+		repository: newTestRepository(proposals...),
+		options:    RepositoryOptions{NetworkLimit: 1},
+	})
+
+	want := map[string]float64{
+		`propmon_access_policy_proposal_count{id="a",source="https://a"}`: 2,
+		`propmon_access_policy_proposal_count{id="other",source="other"}`: 2,
+		`propmon_contact_type_proposal_count{contact_type="contact/a"}`:   2,
+		`propmon_contact_type_proposal_count{contact_type="other"}`:       2,
+		`propmon_labels_folded{label="access_policy"}`:                    2,
+		`propmon_labels_folded{label="contact_type"}`:                     2,
+	}
+	got := withPrefix(samples, "propmon_access_policy_proposal_count")
+	maps.Copy(got, withPrefix(samples, "propmon_contact_type_proposal_count"))
+	maps.Copy(got, withPrefix(samples, "propmon_labels_folded"))
+	delete(got, `propmon_labels_folded{label="asn"}`)
+	delete(got, `propmon_labels_folded{label="isp"}`)
+	if !maps.Equal(got, want) {
+		t.Errorf("samples = %v, want %v", got, want)
+	}
+}
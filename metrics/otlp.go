@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	prombridge "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+const (
+	OTLPProtocolGRPC = "grpc"
+	OTLPProtocolHTTP = "http"
+)
+
+type OTLPOptions struct {
+	// Endpoint is the host:port or url of the collector
+	Endpoint string
+	// Protocol is either grpc or http
+	Protocol string
+	Insecure bool
+	Interval time.Duration
+	Headers  map[string]string
+	// Attributes are added to the resource, which identifies the instance by its hostname by default
+	Attributes map[string]string
+}
+
+// OTLPExporter pushes the metrics of the registry to an OpenTelemetry collector on an interval.
+type OTLPExporter struct {
+	provider *sdkmetric.MeterProvider
+}
+
+// NewOTLPExporter creates an exporter that starts pushing immediately. Push failures are reported to
+// the global OpenTelemetry error handler.
+func NewOTLPExporter(options OTLPOptions) (*OTLPExporter, error) {
+	exporter, err := newOTLPMetricExporter(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+	}
+
+	res, err := newResource(options.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp resource: %w", err)
+	}
+
+	reader := sdkmetric.NewPeriodicReader(exporter,
+		sdkmetric.WithInterval(options.Interval),
+		sdkmetric.WithProducer(prombridge.NewMetricProducer(prombridge.WithGatherer(Registry))),
+	)
+
+	return &OTLPExporter{
+		provider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(res)),
+	}, nil
+}
+
+// Flush pushes the current metrics immediately.
+func (e *OTLPExporter) Flush(ctx context.Context) error {
+	return e.provider.ForceFlush(ctx)
+}
+
+// Shutdown pushes the metrics a last time and stops the exporter.
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	return e.provider.Shutdown(ctx)
+}
+
+func newOTLPMetricExporter(options OTLPOptions) (sdkmetric.Exporter, error) {
+	ctx := context.Background()
+	url := strings.Contains(options.Endpoint, "://")
+
+	switch options.Protocol {
+	case OTLPProtocolGRPC:
+		opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithHeaders(options.Headers)}
+		if url {
+			opts = append(opts, otlpmetricgrpc.WithEndpointURL(options.Endpoint))
+		} else {
+			opts = append(opts, otlpmetricgrpc.WithEndpoint(options.Endpoint))
+		}
+		if options.Insecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		return otlpmetricgrpc.New(ctx, opts...)
+
+	case OTLPProtocolHTTP:
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithHeaders(options.Headers)}
+		if url {
+			opts = append(opts, otlpmetrichttp.WithEndpointURL(options.Endpoint))
+		} else {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(options.Endpoint))
+		}
+		if options.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+
+	default:
+		return nil, fmt.Errorf("unknown otlp protocol %q", options.Protocol)
+	}
+}
+
+func newResource(attributes map[string]string) (*resource.Resource, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName("propmon"),
+		semconv.ServiceInstanceID(hostname),
+		semconv.HostName(hostname),
+	}
+	for key, value := range attributes {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+
+	// attributes set with OTEL_RESOURCE_ATTRIBUTES take precedence over the defaults
+	return resource.Merge(
+		resource.NewWithAttributes(semconv.SchemaURL, attrs...),
+		resource.Environment(),
+	)
+}
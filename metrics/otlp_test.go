@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpReceiver records the metric export requests of the grpc protocol.
+type otlpReceiver struct {
+	collectorpb.UnimplementedMetricsServiceServer
+	requests chan *collectorpb.ExportMetricsServiceRequest
+}
+
+func (r *otlpReceiver) Export(_ context.Context, req *collectorpb.ExportMetricsServiceRequest) (*collectorpb.ExportMetricsServiceResponse, error) {
+	r.requests <- req
+	return &collectorpb.ExportMetricsServiceResponse{}, nil
+}
+
+// startOTLPHTTP starts a collector for the http protocol and returns its endpoint url.
+func startOTLPHTTP(t *testing.T, requests chan *collectorpb.ExportMetricsServiceRequest) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		req := &collectorpb.ExportMetricsServiceRequest{}
+		if err := proto.Unmarshal(body, req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests <- req
+
+		data, _ := proto.Marshal(&collectorpb.ExportMetricsServiceResponse{})
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL + "/v1/metrics"
+}
+
+// startOTLPGRPC starts a collector for the grpc protocol and returns its address.
+func startOTLPGRPC(t *testing.T, requests chan *collectorpb.ExportMetricsServiceRequest) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	collectorpb.RegisterMetricsServiceServer(server, &otlpReceiver{requests: requests})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestOTLPExporter(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		protocol string
+		start    func(*testing.T, chan *collectorpb.ExportMetricsServiceRequest) string
+	}{
+		{OTLPProtocolHTTP, startOTLPHTTP},
+		{OTLPProtocolGRPC, startOTLPGRPC},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			requests := make(chan *collectorpb.ExportMetricsServiceRequest, 16)
+
+			exporter, err := NewOTLPExporter(OTLPOptions{
+				Endpoint:   tt.start(t, requests),
+				Protocol:   tt.protocol,
+				Insecure:   true,
+				Interval:   time.Hour,
+				Attributes: map[string]string{"deployment.environment": "test"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = exporter.Shutdown(context.Background()) })
+
+			ProposalPing()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := exporter.Flush(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			var req *collectorpb.ExportMetricsServiceRequest
+			select {
+			case req = <-requests:
+			case <-ctx.Done():
+				t.Fatal("no metrics received")
+			}
+
+			for _, rm := range req.GetResourceMetrics() {
+				attributes := make(map[string]string)
+				for _, kv := range rm.GetResource().GetAttributes() {
+					attributes[kv.GetKey()] = kv.GetValue().GetStringValue()
+				}
+				want := map[string]string{
+					"service.name":           "propmon",
+					"service.instance.id":    hostname,
+					"deployment.environment": "test",
+				}
+				for key, value := range want {
+					if attributes[key] != value {
+						t.Errorf("resource attribute %s = %q, want %q", key, attributes[key], value)
+					}
+				}
+
+				if ping := findOTLPMetric(rm, "propmon_proposal_ping"); ping == nil {
+					t.Error("propmon_proposal_ping was not exported")
+				} else if points := ping.GetSum().GetDataPoints(); len(points) != 1 || points[0].GetAsDouble() < 1 || !ping.GetSum().GetIsMonotonic() {
+					t.Errorf("propmon_proposal_ping = %v, want a monotonic sum of at least 1", ping)
+				}
+			}
+			if len(req.GetResourceMetrics()) != 1 {
+				t.Errorf("got %d resources, want 1", len(req.GetResourceMetrics()))
+			}
+		})
+	}
+}
+
+func findOTLPMetric(rm *metricspb.ResourceMetrics, name string) *metricspb.Metric {
+	for _, sm := range rm.GetScopeMetrics() {
+		for _, m := range sm.GetMetrics() {
+			if m.GetName() == name {
+				return m
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+// qualityMetric exports one value of the provider quality data per country and node type as an average,
+// as sum and count gauges that can be aggregated across labels and as a histogram.
+type qualityMetric struct {
+	average   *prometheus.Desc
+	sum       *prometheus.Desc
+	count     *prometheus.Desc
+	histogram *prometheus.Desc
+	buckets   []float64
+	value     func(q *proposal.Quality) float64
+}
+
+var qualityMetrics = []qualityMetric{
+	newQualityMetric("quality", "quality score", []float64{0.5, 1, 1.5, 2, 2.5, 3},
+		func(q *proposal.Quality) float64 { return q.Quality }),
+	newQualityMetric("latency", "latency", []float64{25, 50, 100, 200, 400, 800, 1600},
+		func(q *proposal.Quality) float64 { return q.Latency }),
+	newQualityMetric("bandwidth", "bandwidth", []float64{1, 5, 10, 25, 50, 100, 250, 500},
+		func(q *proposal.Quality) float64 { return q.Bandwidth }),
+	newQualityMetric("uptime", "uptime", []float64{1, 4, 8, 12, 16, 20, 24},
+		func(q *proposal.Quality) float64 { return q.Uptime }),
+}
+
+func newQualityMetric(name, help string, buckets []float64, value func(q *proposal.Quality) float64) qualityMetric {
+	labels := []string{"country", "node_type"}
+	return qualityMetric{
+		average: prometheus.NewDesc("propmon_"+name, "Average "+help+" for country and node type",
+			labels, nil),
+		sum: prometheus.NewDesc("propmon_"+name+"_sum", "Sum of the provider "+help+" for country and node type",
+			labels, nil),
+		count: prometheus.NewDesc("propmon_"+name+"_count", "Number of providers with "+help+" data for country and node type",
+			labels, nil),
+		histogram: prometheus.NewDesc("propmon_"+name+"_distribution", "Distribution of the provider "+help+" for country and node type",
+			labels, nil),
+		buckets: buckets,
+		value:   value,
+	}
+}
+
+// distribution accumulates the observations of a classic histogram.
+type distribution struct {
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+func newDistribution(bounds []float64) *distribution {
+	buckets := make(map[float64]uint64, len(bounds))
+	for _, bound := range bounds {
+		buckets[bound] = 0
+	}
+	return &distribution{buckets: buckets}
+}
+
+func (d *distribution) observe(v float64) {
+	d.count++
+	d.sum += v
+	for bound := range d.buckets {
+		if v <= bound {
+			d.buckets[bound]++
+		}
+	}
+}
+
+// qualities holds one distribution per quality metric.
+type qualities []*distribution
+
+func newQualities() qualities {
+	q := make(qualities, len(qualityMetrics))
+	for i, m := range qualityMetrics {
+		q[i] = newDistribution(m.buckets)
+	}
+	return q
+}
+
+func (q qualities) observe(quality *proposal.Quality) {
+	for i, m := range qualityMetrics {
+		q[i].observe(m.value(quality))
+	}
+}
+
+func describeQualities(ch chan<- *prometheus.Desc) {
+	for _, m := range qualityMetrics {
+		ch <- m.average
+		ch <- m.sum
+		ch <- m.count
+		ch <- m.histogram
+	}
+}
+
+func collectQualities(ch chan<- prometheus.Metric, q qualities, labels ...string) {
+	for i, m := range qualityMetrics {
+		d := q[i]
+		if d.count == 0 {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(m.average, prometheus.GaugeValue, d.sum/float64(d.count), labels...)
+		ch <- prometheus.MustNewConstMetric(m.sum, prometheus.GaugeValue, d.sum, labels...)
+		ch <- prometheus.MustNewConstMetric(m.count, prometheus.GaugeValue, float64(d.count), labels...)
+		ch <- prometheus.MustNewConstHistogram(m.histogram, d.count, d.sum, d.buckets, labels...)
+	}
+}
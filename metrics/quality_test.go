@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func TestDistribution(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   map[float64]uint64
+		sum    float64
+	}{
+		{"empty", nil, map[float64]uint64{1: 0, 5: 0}, 0},
+		{"upper bound inclusive", []float64{1}, map[float64]uint64{1: 1, 5: 1}, 1},
+		{"cumulative", []float64{0.5, 3, 4}, map[float64]uint64{1: 1, 5: 3}, 7.5},
+		{"above all buckets", []float64{10}, map[float64]uint64{1: 0, 5: 0}, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDistribution([]float64{1, 5})
+			for _, v := range tt.values {
+				d.observe(v)
+			}
+
+			if !maps.Equal(d.buckets, tt.want) {
+				t.Errorf("buckets = %v, want %v", d.buckets, tt.want)
+			}
+			if d.count != uint64(len(tt.values)) || d.sum != tt.sum {
+				t.Errorf("count = %d, sum = %v, want %d and %v", d.count, d.sum, len(tt.values), tt.sum)
+			}
+		})
+	}
+}
+
+func TestQualityHistograms(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(&repositoryCollector{repository: newTestRepository(
+		&proposal.Proposal{ProviderID: "0xaaa", ServiceType: "wireguard", Location: proposal.Location{Country: "DE", IpType: "residential"},
+			Quality: &proposal.Quality{Latency: 30}},
+		&proposal.Proposal{ProviderID: "0xbbb", ServiceType: "wireguard", Location: proposal.Location{Country: "DE", IpType: "residential"},
+			Quality: &proposal.Quality{Latency: 150}},
+	)})
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, family := range families {
+		if family.GetName() != "propmon_latency_distribution" {
+			continue
+		}
+		found = true
+
+		h := family.GetMetric()[0].GetHistogram()
+		if h.GetSampleCount() != 2 || h.GetSampleSum() != 180 {
+			t.Errorf("count = %d, sum = %v, want 2 and 180", h.GetSampleCount(), h.GetSampleSum())
+		}
+
+		want := map[float64]uint64{25: 0, 50: 1, 100: 1, 200: 2, 400: 2, 800: 2, 1600: 2}
+		got := make(map[float64]uint64)
+		for _, b := range h.GetBucket() {
+			got[b.GetUpperBound()] = b.GetCumulativeCount()
+		}
+		if !maps.Equal(got, want) {
+			t.Errorf("buckets = %v, want %v", got, want)
+		}
+	}
+
+	if !found {
+		t.Error("propmon_latency_distribution is not exported")
+	}
+}
+
+func TestQualityAggregates(t *testing.T) {
+	samples := gather(t, &repositoryCollector{repository: newTestRepository(
+		&proposal.Proposal{ProviderID: "0xaaa", ServiceType: "wireguard", Location: proposal.Location{Country: "DE", IpType: "residential"},
+			Quality: &proposal.Quality{Latency: 30}},
+		&proposal.Proposal{ProviderID: "0xbbb", ServiceType: "wireguard", Location: proposal.Location{Country: "DE", IpType: "residential"},
+			Quality: &proposal.Quality{Latency: 150}},
+		&proposal.Proposal{ProviderID: "0xccc", ServiceType: "wireguard", Location: proposal.Location{Country: "US", IpType: "residential"},
+			Quality: &proposal.Quality{Latency: 300}},
+	)})
+
+	de := `{country="DE",node_type="residential"}`
+	us := `{country="US",node_type="residential"}`
+	want := map[string]float64{
+		"propmon_latency" + de:       90,
+		"propmon_latency_sum" + de:   180,
+		"propmon_latency_count" + de: 2,
+		"propmon_latency" + us:       300,
+		"propmon_latency_sum" + us:   300,
+		"propmon_latency_count" + us: 1,
+	}
+	for key, v := range want {
+		got, ok := samples[key]
+		if !ok {
+			t.Errorf("%s is not exported", key)
+		} else if got != v {
+			t.Errorf("%s = %v, want %v", key, got, v)
+		}
+	}
+
+	// the average across countries weights every provider equally
+	sum := samples["propmon_latency_sum"+de] + samples["propmon_latency_sum"+us]
+	count := samples["propmon_latency_count"+de] + samples["propmon_latency_count"+us]
+	if avg := sum / count; avg != 160 {
+		t.Errorf("average across countries = %v, want 160", avg)
+	}
+}
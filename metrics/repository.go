@@ -0,0 +1,223 @@
+package metrics
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+var (
+	proposalCountDesc = prometheus.NewDesc("propmon_proposal_count", "Service Proposal count",
+		[]string{"service_type"}, nil)
+	providerCountDesc = prometheus.NewDesc("propmon_provider_count", "Provider count",
+		[]string{"country", "node_type"}, nil)
+	restrictedProviderCountDesc = prometheus.NewDesc("propmon_restricted_provider_count", "Count of providers with restricted nodes",
+		[]string{"country", "node_type"}, nil)
+	accessPolicyCountDesc = prometheus.NewDesc("propmon_access_policy_proposal_count", "Service Proposal count per access policy",
+		[]string{"id", "source"}, nil)
+	compatibilityCountDesc = prometheus.NewDesc("propmon_compatibility_proposal_count", "Service Proposal count per compatibility level",
+		[]string{"compatibility"}, nil)
+	contactTypeCountDesc = prometheus.NewDesc("propmon_contact_type_proposal_count", "Service Proposal count per contact type",
+		[]string{"contact_type"}, nil)
+)
+
+// otherAccessPolicy is the label set the access policies beyond the top n are folded into.
+var otherAccessPolicy = proposal.AccessPolicy{ID: otherNetwork, Source: otherNetwork}
+
+func compareAccessPolicies(a, b proposal.AccessPolicy) int {
+	return cmp.Or(strings.Compare(a.ID, b.ID), strings.Compare(a.Source, b.Source))
+}
+
+type providerLabel struct {
+	Country  string
+	NodeType string
+}
+
+// snapshot holds the repository gauges of one point in time.
+type snapshot struct {
+	proposals map[string]int
+	providers map[providerLabel]int
+	qualities map[providerLabel]qualities
+	// restricted counts the providers with restricted nodes
+	restricted     map[providerLabel]int
+	accessPolicies map[proposal.AccessPolicy]int
+	compatibility  map[int]int
+	contactTypes   map[string]int
+	asns           networks
+	isps           networks
+	churn          map[proposal.ChurnKey]proposal.Churn
+	churnWindows   []churnWindow
+	// churnPopulation counts the proposals per churn key
+	churnPopulation map[proposal.ChurnKey]int
+}
+
+// newSnapshot computes the gauges from a single read of the proposals, so they are consistent with each other.
+// Like Repository.Providers, the location and quality of a provider are taken from one of its proposals.
+func newSnapshot(proposals []*proposal.Proposal) *snapshot {
+	s := &snapshot{
+		proposals:       make(map[string]int),
+		providers:       make(map[providerLabel]int),
+		qualities:       make(map[providerLabel]qualities),
+		restricted:      make(map[providerLabel]int),
+		accessPolicies:  make(map[proposal.AccessPolicy]int),
+		compatibility:   make(map[int]int),
+		contactTypes:    make(map[string]int),
+		asns:            make(networks),
+		isps:            make(networks),
+		churnPopulation: make(map[proposal.ChurnKey]int),
+	}
+
+	seen := make(map[string]struct{})
+	for _, p := range proposals {
+		s.proposals[p.ServiceType]++
+		s.churnPopulation[proposal.ChurnKey{Country: p.Location.Country, ServiceType: p.ServiceType}]++
+		s.compatibility[p.Compatibility]++
+		for _, policy := range p.AccessPolicies {
+			s.accessPolicies[policy]++
+		}
+		for _, contact := range p.Contacts {
+			s.contactTypes[contact.Type]++
+		}
+
+		if _, ok := seen[p.ProviderID]; ok {
+			continue
+		}
+		seen[p.ProviderID] = struct{}{}
+
+		label := providerLabel{
+			Country:  p.Location.Country,
+			NodeType: p.Location.IpType,
+		}
+		s.providers[label]++
+		s.asns.add(asnLabel(p.Location.Asn), p.Quality)
+		s.isps.add(p.Location.Isp, p.Quality)
+
+		if p.Quality == nil {
+			continue
+		}
+
+		if s.qualities[label] == nil {
+			s.qualities[label] = newQualities()
+		}
+		s.qualities[label].observe(p.Quality)
+
+		if p.Quality.RestrictedNode {
+			s.restricted[label]++
+		}
+	}
+
+	return s
+}
+
+// repositoryCollector computes the repository gauges at scrape time. Snapshots are reused for the
+// cache ttl, so rapid scrapes do not each scan the repository.
+type repositoryCollector struct {
+	repository *proposal.Repository
+	options    RepositoryOptions
+	snapshot   *snapshot
+	taken      time.Time
+	mu         sync.Mutex
+}
+
+type RepositoryOptions struct {
+	// CacheTTL is the duration a snapshot of the repository is reused for
+	CacheTTL time.Duration
+	// NetworkLimit is the number of ASNs, ISPs, access policies and contact types with the most providers
+	// or proposals that are exported individually
+	NetworkLimit int
+	// ChurnWindows are the windows the churn rate is computed over
+	ChurnWindows []time.Duration
+}
+
+// RegisterRepository exports the proposal, provider, quality and churn metrics of the repository.
+func RegisterRepository(repository *proposal.Repository, options RepositoryOptions) {
+	for _, window := range options.ChurnWindows {
+		repository.RetainChurn(window)
+	}
+
+	Registry.MustRegister(&repositoryCollector{
+		repository: repository,
+		options:    options,
+	})
+}
+
+func (c *repositoryCollector) get() *snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.snapshot == nil || time.Since(c.taken) > c.options.CacheTTL {
+		c.snapshot = newSnapshot(c.repository.Proposals())
+		c.snapshot.churn = c.repository.Churn()
+		for _, window := range c.options.ChurnWindows {
+			c.snapshot.churnWindows = append(c.snapshot.churnWindows, churnWindow{
+				window: window,
+				churn:  c.repository.ChurnSince(window),
+			})
+		}
+		c.taken = time.Now()
+	}
+
+	return c.snapshot
+}
+
+func (c *repositoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- proposalCountDesc
+	ch <- providerCountDesc
+	ch <- restrictedProviderCountDesc
+	ch <- accessPolicyCountDesc
+	ch <- compatibilityCountDesc
+	ch <- contactTypeCountDesc
+	describeQualities(ch)
+	describeChurn(ch)
+	ch <- asnProviderCountDesc
+	ch <- asnQualityDesc
+	ch <- ispProviderCountDesc
+	ch <- ispQualityDesc
+	ch <- labelsFoldedDesc
+}
+
+func (c *repositoryCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.get()
+
+	for serviceType, n := range s.proposals {
+		ch <- prometheus.MustNewConstMetric(proposalCountDesc, prometheus.GaugeValue, float64(n), serviceType)
+	}
+
+	policies, folded := topCounts(s.accessPolicies, c.options.NetworkLimit, otherAccessPolicy, compareAccessPolicies)
+	ch <- prometheus.MustNewConstMetric(labelsFoldedDesc, prometheus.GaugeValue, float64(folded), "access_policy")
+	for policy, n := range policies {
+		ch <- prometheus.MustNewConstMetric(accessPolicyCountDesc, prometheus.GaugeValue, float64(n), policy.ID, policy.Source)
+	}
+
+	for compatibility, n := range s.compatibility {
+		ch <- prometheus.MustNewConstMetric(compatibilityCountDesc, prometheus.GaugeValue, float64(n), strconv.Itoa(compatibility))
+	}
+
+	contactTypes, folded := topCounts(s.contactTypes, c.options.NetworkLimit, otherNetwork, strings.Compare)
+	ch <- prometheus.MustNewConstMetric(labelsFoldedDesc, prometheus.GaugeValue, float64(folded), "contact_type")
+	for contactType, n := range contactTypes {
+		ch <- prometheus.MustNewConstMetric(contactTypeCountDesc, prometheus.GaugeValue, float64(n), contactType)
+	}
+
+	for label, n := range s.restricted {
+		ch <- prometheus.MustNewConstMetric(restrictedProviderCountDesc, prometheus.GaugeValue, float64(n), label.Country, label.NodeType)
+	}
+
+	for label, count := range s.providers {
+		ch <- prometheus.MustNewConstMetric(providerCountDesc, prometheus.GaugeValue, float64(count), label.Country, label.NodeType)
+
+		if q, ok := s.qualities[label]; ok {
+			collectQualities(ch, q, label.Country, label.NodeType)
+		}
+	}
+
+	collectNetworks(ch, "asn", s.asns, c.options.NetworkLimit, asnProviderCountDesc, asnQualityDesc)
+	collectNetworks(ch, "isp", s.isps, c.options.NetworkLimit, ispProviderCountDesc, ispQualityDesc)
+	collectChurn(ch, s)
+}
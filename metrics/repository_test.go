@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func testProposals() []*proposal.Proposal {
+	return []*proposal.Proposal{
+		{
+			ProviderID:     "0xaaa",
+			ServiceType:    "wireguard",
+			Compatibility:  2,
+			Location:       proposal.Location{Country: "DE", IpType: "residential", Asn: 3320, Isp: "DTAG"},
+			Contacts:       []proposal.Contact{{Type: "nats/p2p/v1"}},
+			AccessPolicies: []proposal.AccessPolicy{{ID: "mysterium", Source: "https://trust.mysterium.network"}},
+			Quality:        &proposal.Quality{Quality: 2, Latency: 40, Bandwidth: 80, Uptime: 20, RestrictedNode: true},
+		},
+		{
+			ProviderID:    "0xaaa",
+			ServiceType:   "openvpn",
+			Compatibility: 1,
+			Location:      proposal.Location{Country: "DE", IpType: "residential", Asn: 3320, Isp: "DTAG"},
+			Contacts:      []proposal.Contact{{Type: "nats/p2p/v1"}},
+			Quality:       &proposal.Quality{Quality: 2, Latency: 40, Bandwidth: 80, Uptime: 20, RestrictedNode: true},
+		},
+		{
+			ProviderID:    "0xbbb",
+			ServiceType:   "wireguard",
+			Compatibility: 2,
+			Location:      proposal.Location{Country: "DE", IpType: "residential", Asn: 3320, Isp: "DTAG"},
+			Quality:       &proposal.Quality{Quality: 1, Latency: 60, Bandwidth: 20, Uptime: 10},
+		},
+		{
+			ProviderID:    "0xccc",
+			ServiceType:   "wireguard",
+			Compatibility: 2,
+			Location:      proposal.Location{Country: "US", IpType: "hosting", Asn: 16509, Isp: "Amazon"},
+		},
+	}
+}
+
+func newTestRepository(proposals ...*proposal.Proposal) *proposal.Repository {
+	r := proposal.NewProposalRepository(time.Hour)
+	for _, p := range proposals {
+		r.Store(p)
+	}
+	return r
+}
+
+func TestRepositoryCollector(t *testing.T) {
+	samples := gather(t, &repositoryCollector{repository: newTestRepository(testProposals()...)})
+
+	tests := []struct {
+		key  string
+		want float64
+	}{
+		{`propmon_proposal_count{service_type="wireguard"}`, 3},
+		{`propmon_proposal_count{service_type="openvpn"}`, 1},
+		// providers are counted once, regardless of their number of proposals
+		{`propmon_provider_count{country="DE",node_type="residential"}`, 2},
+		{`propmon_provider_count{country="US",node_type="hosting"}`, 1},
+		{`propmon_restricted_provider_count{country="DE",node_type="residential"}`, 1},
+		{`propmon_compatibility_proposal_count{compatibility="2"}`, 3},
+		{`propmon_compatibility_proposal_count{compatibility="1"}`, 1},
+		{`propmon_contact_type_proposal_count{contact_type="nats/p2p/v1"}`, 2},
+		{`propmon_access_policy_proposal_count{id="mysterium",source="https://trust.mysterium.network"}`, 1},
+		{`propmon_quality{country="DE",node_type="residential"}`, 1.5},
+		{`propmon_latency{country="DE",node_type="residential"}`, 50},
+		{`propmon_quality_distribution{country="DE",node_type="residential"}`, 2},
+		{`propmon_asn_provider_count{asn="3320"}`, 2},
+		{`propmon_isp_provider_count{isp="Amazon"}`, 1},
+	}
+
+	for _, tt := range tests {
+		if got, ok := samples[tt.key]; !ok || got != tt.want {
+			t.Errorf("%s = %v (present %v), want %v", tt.key, got, ok, tt.want)
+		}
+	}
+
+	// providers without quality data do not export quality gauges
+	if q := withPrefix(samples, "propmon_quality"); len(q) != 1 {
+		t.Errorf("quality samples = %v, want only the DE residential average", q)
+	}
+}
+
+func TestRepositoryCollectorStaleLabels(t *testing.T) {
+	r := newTestRepository(testProposals()...)
+	c := &repositoryCollector{repository: r}
+
+	if samples := gather(t, c); samples[`propmon_provider_count{country="US",node_type="hosting"}`] != 1 {
+		t.Fatal("US provider missing")
+	}
+
+	r.Remove(testProposals()[3].ServiceKey())
+
+	samples := gather(t, c)
+	for _, key := range []string{
+		`propmon_provider_count{country="US",node_type="hosting"}`,
+		`propmon_asn_provider_count{asn="16509"}`,
+		`propmon_isp_provider_count{isp="Amazon"}`,
+	} {
+		if _, ok := samples[key]; ok {
+			t.Errorf("%s is still exported after the provider was removed", key)
+		}
+	}
+}
+
+func TestRepositoryCollectorCache(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		want float64
+	}{
+		{"cached", time.Hour, 3},
+		{"expired", 0, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepository(testProposals()...)
+			c := &repositoryCollector{repository: r, options: RepositoryOptions{CacheTTL: tt.ttl}}
+			first := gather(t, c)
+
+			r.Store(&proposal.Proposal{ProviderID: "0xddd", ServiceType: "wireguard"})
+
+			second := gather(t, c)
+			if got := second[`propmon_proposal_count{service_type="wireguard"}`]; got != tt.want {
+				t.Errorf("wireguard proposals = %v, want %v", got, tt.want)
+			}
+			if tt.ttl > 0 && !maps.Equal(first, second) {
+				t.Error("cached scrape differs from the first one")
+			}
+		})
+	}
+}
+
+func TestChurnEvictions(t *testing.T) {
+	r := proposal.NewProposalRepository(time.Hour)
+	r.RetainChurn(time.Hour)
+	for _, id := range []string{"0xaaa", "0xbbb", "0xccc", "0xddd"} {
+		r.Store(&proposal.Proposal{ProviderID: id, ServiceType: "wireguard", Location: proposal.Location{Country: "DE"}})
+	}
+	r.Remove((&proposal.Proposal{ProviderID: "0xaaa", ServiceType: "wireguard"}).ServiceKey())
+	r.RemoveProvider("0xbbb")
+
+	samples := gather(t, &repositoryCollector{repository: r, options: RepositoryOptions{ChurnWindows: []time.Duration{time.Hour}}})
+
+	// evictions are departures like unregistrations and expiries
+	want := map[string]float64{
+		`propmon_churn_arrivals{country="DE",service_type="wireguard"}`:               4,
+		`propmon_churn_unregistrations{country="DE",service_type="wireguard"}`:        1,
+		`propmon_churn_expiries{country="DE",service_type="wireguard"}`:               0,
+		`propmon_churn_evictions{country="DE",service_type="wireguard"}`:              1,
+		`propmon_churn_net_change{country="DE",service_type="wireguard",window="1h"}`: 2,
+	}
+	for key, v := range want {
+		got, ok := samples[key]
+		if !ok {
+			t.Errorf("%s is not exported", key)
+		} else if got != v {
+			t.Errorf("%s = %v, want %v", key, got, v)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package metrics
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+var (
+	watchedOnlineDesc = prometheus.NewDesc("propmon_watched_provider_online", "Whether a watched provider has active proposals",
+		[]string{"provider_id"}, nil)
+	watchedLastPingAgeDesc = prometheus.NewDesc("propmon_watched_provider_last_ping_age_seconds", "Seconds since a watched provider was last seen",
+		[]string{"provider_id"}, nil)
+	watchedPingsDesc = prometheus.NewDesc("propmon_watched_provider_pings", "Pings received from a watched provider since it was added to the watchlist",
+		[]string{"provider_id"}, nil)
+	watchedProposalsDesc = prometheus.NewDesc("propmon_watched_provider_proposals", "Service Proposal count of a watched provider",
+		[]string{"provider_id"}, nil)
+	watchedQualityDesc = prometheus.NewDesc("propmon_watched_provider_quality", "Quality score of a watched provider",
+		[]string{"provider_id"}, nil)
+	watchedLatencyDesc = prometheus.NewDesc("propmon_watched_provider_latency", "Latency of a watched provider",
+		[]string{"provider_id"}, nil)
+	watchedBandwidthDesc = prometheus.NewDesc("propmon_watched_provider_bandwidth", "Bandwidth of a watched provider",
+		[]string{"provider_id"}, nil)
+	watchedUptimeDesc = prometheus.NewDesc("propmon_watched_provider_uptime", "Uptime of a watched provider",
+		[]string{"provider_id"}, nil)
+)
+
+// Watchlist is the set of providers exported individually. It remembers when each provider was last
+// seen, so the last ping age keeps growing after a provider went offline, and counts their pings,
+// so the counter does not drop when their proposals expire.
+type Watchlist struct {
+	lastSeen map[string]time.Time
+	pings    map[string]uint64
+	mu       sync.Mutex
+}
+
+type watchedProvider struct {
+	lastSeen time.Time
+	pings    uint64
+}
+
+func NewWatchlist(ids []string) *Watchlist {
+	w := &Watchlist{
+		lastSeen: make(map[string]time.Time),
+		pings:    make(map[string]uint64),
+	}
+	for _, id := range ids {
+		w.Add(id)
+	}
+	return w
+}
+
+// Add watches a provider and reports whether it was not watched before.
+func (w *Watchlist) Add(id string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.lastSeen[id]; ok {
+		return false
+	}
+	w.lastSeen[id] = time.Time{}
+	w.pings[id] = 0
+	return true
+}
+
+// Remove stops watching a provider and reports whether it was watched.
+func (w *Watchlist) Remove(id string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.lastSeen[id]; !ok {
+		return false
+	}
+	delete(w.lastSeen, id)
+	delete(w.pings, id)
+	return true
+}
+
+// Ping counts a ping of the provider if it is watched.
+func (w *Watchlist) Ping(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.pings[id]; ok {
+		w.pings[id]++
+	}
+}
+
+// IDs returns the watched provider ids in order.
+func (w *Watchlist) IDs() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ids := make([]string, 0, len(w.lastSeen))
+	for id := range w.lastSeen {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// seen records the last activity of the watched providers and returns when each of them was last seen
+// and their ping count.
+func (w *Watchlist) seen(activity map[string]*proposal.ProviderActivity) map[string]watchedProvider {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	providers := make(map[string]watchedProvider, len(w.lastSeen))
+	for id, t := range w.lastSeen {
+		if a, ok := activity[id]; ok && a.LastSeen.After(t) {
+			t = a.LastSeen
+			w.lastSeen[id] = t
+		}
+		providers[id] = watchedProvider{lastSeen: t, pings: w.pings[id]}
+	}
+	return providers
+}
+
+type watchlistCollector struct {
+	repository *proposal.Repository
+	watchlist  *Watchlist
+}
+
+// RegisterWatchlist exports the activity and quality of the watched providers labelled by their id.
+func RegisterWatchlist(repository *proposal.Repository, watchlist *Watchlist) {
+	Registry.MustRegister(&watchlistCollector{
+		repository: repository,
+		watchlist:  watchlist,
+	})
+}
+
+func (c *watchlistCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- watchedOnlineDesc
+	ch <- watchedLastPingAgeDesc
+	ch <- watchedPingsDesc
+	ch <- watchedProposalsDesc
+	ch <- watchedQualityDesc
+	ch <- watchedLatencyDesc
+	ch <- watchedBandwidthDesc
+	ch <- watchedUptimeDesc
+}
+
+func (c *watchlistCollector) Collect(ch chan<- prometheus.Metric) {
+	activity := c.repository.Activity(c.watchlist.IDs())
+	watched := c.watchlist.seen(activity)
+
+	for id, w := range watched {
+		ch <- prometheus.MustNewConstMetric(watchedPingsDesc, prometheus.CounterValue, float64(w.pings), id)
+
+		a, online := activity[id]
+		if !online {
+			ch <- prometheus.MustNewConstMetric(watchedOnlineDesc, prometheus.GaugeValue, 0, id)
+			if !w.lastSeen.IsZero() {
+				ch <- prometheus.MustNewConstMetric(watchedLastPingAgeDesc, prometheus.GaugeValue, time.Since(w.lastSeen).Seconds(), id)
+			}
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(watchedOnlineDesc, prometheus.GaugeValue, 1, id)
+		ch <- prometheus.MustNewConstMetric(watchedLastPingAgeDesc, prometheus.GaugeValue, time.Since(w.lastSeen).Seconds(), id)
+		ch <- prometheus.MustNewConstMetric(watchedProposalsDesc, prometheus.GaugeValue, float64(a.Proposals), id)
+
+		if a.Quality == nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(watchedQualityDesc, prometheus.GaugeValue, a.Quality.Quality, id)
+		ch <- prometheus.MustNewConstMetric(watchedLatencyDesc, prometheus.GaugeValue, a.Quality.Latency, id)
+		ch <- prometheus.MustNewConstMetric(watchedBandwidthDesc, prometheus.GaugeValue, a.Quality.Bandwidth, id)
+		ch <- prometheus.MustNewConstMetric(watchedUptimeDesc, prometheus.GaugeValue, a.Quality.Uptime, id)
+	}
+}
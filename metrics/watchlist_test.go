@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func TestWatchlist(t *testing.T) {
+	w := NewWatchlist([]string{"0xbbb", "0xaaa", "0xbbb"})
+
+	if ids := w.IDs(); !slices.Equal(ids, []string{"0xaaa", "0xbbb"}) {
+		t.Errorf("IDs = %v", ids)
+	}
+
+	tests := []struct {
+		name string
+		op   func(string) bool
+		id   string
+		want bool
+	}{
+		{"add new", w.Add, "0xccc", true},
+		{"add watched", w.Add, "0xaaa", false},
+		{"remove watched", w.Remove, "0xccc", true},
+		{"remove unwatched", w.Remove, "0xccc", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.op(tt.id); got != tt.want {
+			t.Errorf("%s %s = %v, want %v", tt.name, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestWatchlistCollector(t *testing.T) {
+	online := &proposal.Proposal{ProviderID: "0xaaa", ServiceType: "wireguard", Quality: &proposal.Quality{Quality: 2, Latency: 30}}
+	r := newTestRepository(online, &proposal.Proposal{ProviderID: "0xbbb", ServiceType: "wireguard"})
+
+	w := NewWatchlist([]string{"0xaaa", "0xbbb", "0xccc"})
+	c := &watchlistCollector{repository: r, watchlist: w}
+	for range 3 {
+		r.RenewOrStore(online)
+		w.Ping("0xaaa")
+	}
+	// pings of providers that are not watched are ignored
+	w.Ping("0xddd")
+
+	samples := gather(t, c)
+	tests := []struct {
+		key  string
+		want float64
+	}{
+		{`propmon_watched_provider_online{provider_id="0xaaa"}`, 1},
+		{`propmon_watched_provider_online{provider_id="0xbbb"}`, 1},
+		{`propmon_watched_provider_online{provider_id="0xccc"}`, 0},
+		{`propmon_watched_provider_pings{provider_id="0xaaa"}`, 3},
+		{`propmon_watched_provider_pings{provider_id="0xccc"}`, 0},
+		{`propmon_watched_provider_proposals{provider_id="0xaaa"}`, 1},
+		{`propmon_watched_provider_quality{provider_id="0xaaa"}`, 2},
+		{`propmon_watched_provider_latency{provider_id="0xaaa"}`, 30},
+	}
+	for _, tt := range tests {
+		if got, ok := samples[tt.key]; !ok || got != tt.want {
+			t.Errorf("%s = %v (present %v), want %v", tt.key, got, ok, tt.want)
+		}
+	}
+	for _, key := range []string{
+		`propmon_watched_provider_quality{provider_id="0xbbb"}`,
+		`propmon_watched_provider_last_ping_age_seconds{provider_id="0xccc"}`,
+		`propmon_watched_provider_pings{provider_id="0xddd"}`,
+	} {
+		if _, ok := samples[key]; ok {
+			t.Errorf("%s is exported", key)
+		}
+	}
+
+	// after the provider went offline its ping counter must not drop and the last ping age is kept
+	r.Remove(online.ServiceKey())
+	samples = gather(t, c)
+	if got := samples[`propmon_watched_provider_pings{provider_id="0xaaa"}`]; got != 3 {
+		t.Errorf("pings after going offline = %v, want 3", got)
+	}
+	if samples[`propmon_watched_provider_online{provider_id="0xaaa"}`] != 0 {
+		t.Error("provider is still online")
+	}
+	if _, ok := samples[`propmon_watched_provider_last_ping_age_seconds{provider_id="0xaaa"}`]; !ok {
+		t.Error("last ping age of the offline provider is missing")
+	}
+
+	// removing a provider from the watchlist resets its counter
+	w.Remove("0xaaa")
+	w.Add("0xaaa")
+	if got := gather(t, c)[`propmon_watched_provider_pings{provider_id="0xaaa"}`]; got != 0 {
+		t.Errorf("pings after watching again = %v, want 0", got)
+	}
+}
@@ -0,0 +1,150 @@
+package proposal
+
+import (
+	"time"
+)
+
+// churnResolution is the granularity of the churn history.
+const churnResolution = time.Minute
+
+// ChurnKey groups churn by country and service type.
+type ChurnKey struct {
+	Country     string
+	ServiceType string
+}
+
+// Churn counts the proposals that appeared and disappeared.
+type Churn struct {
+	Arrivals        uint64
+	Unregistrations uint64
+	Expiries        uint64
+	Evictions       uint64
+}
+
+// Departures returns the number of proposals that disappeared.
+func (c Churn) Departures() uint64 {
+	return c.Unregistrations + c.Expiries + c.Evictions
+}
+
+func (c *Churn) add(o *Churn) {
+	c.Arrivals += o.Arrivals
+	c.Unregistrations += o.Unregistrations
+	c.Expiries += o.Expiries
+	c.Evictions += o.Evictions
+}
+
+type churnBucket struct {
+	start time.Time
+	churn map[ChurnKey]*Churn
+}
+
+// churnTracker counts churn since the start and keeps a history of the churn per minute for the retention.
+type churnTracker struct {
+	total     map[ChurnKey]*Churn
+	history   []churnBucket
+	retention time.Duration
+}
+
+func newChurnTracker() *churnTracker {
+	return &churnTracker{total: make(map[ChurnKey]*Churn)}
+}
+
+func churnKey(p *Proposal) ChurnKey {
+	return ChurnKey{Country: p.Location.Country, ServiceType: p.ServiceType}
+}
+
+func (t *churnTracker) record(p *Proposal, count func(c *Churn)) {
+	key := churnKey(p)
+	if t.total[key] == nil {
+		t.total[key] = &Churn{}
+	}
+	count(t.total[key])
+
+	if t.retention == 0 {
+		return
+	}
+
+	now := time.Now()
+	t.expire(now)
+	if len(t.history) == 0 || now.Sub(t.history[len(t.history)-1].start) >= churnResolution {
+		t.history = append(t.history, churnBucket{start: now.Truncate(churnResolution), churn: make(map[ChurnKey]*Churn)})
+	}
+
+	bucket := t.history[len(t.history)-1].churn
+	if bucket[key] == nil {
+		bucket[key] = &Churn{}
+	}
+	count(bucket[key])
+}
+
+// expire drops the buckets older than the retention.
+func (t *churnTracker) expire(now time.Time) {
+	var i int
+	for i < len(t.history) && now.Sub(t.history[i].start) > t.retention+churnResolution {
+		i++
+	}
+	t.history = t.history[i:]
+}
+
+func (t *churnTracker) arrived(p *Proposal) {
+	t.record(p, func(c *Churn) { c.Arrivals++ })
+}
+
+func (t *churnTracker) unregistered(p *Proposal) {
+	t.record(p, func(c *Churn) { c.Unregistrations++ })
+}
+
+func (t *churnTracker) expired(p *Proposal) {
+	t.record(p, func(c *Churn) { c.Expiries++ })
+}
+
+func (t *churnTracker) evicted(p *Proposal) {
+	t.record(p, func(c *Churn) { c.Evictions++ })
+}
+
+// RetainChurn keeps the churn history for at least the window, so it can be read with ChurnSince.
+func (r *Repository) RetainChurn(window time.Duration) {
+	r.lock()
+	defer r.mu.Unlock()
+
+	r.churn.retention = max(r.churn.retention, window)
+}
+
+// Churn returns the churn since the start of the repository.
+func (r *Repository) Churn() map[ChurnKey]Churn {
+	r.rlock()
+	defer r.mu.RUnlock()
+
+	churn := make(map[ChurnKey]Churn, len(r.churn.total))
+	for key, c := range r.churn.total {
+		churn[key] = *c
+	}
+	return churn
+}
+
+// ChurnSince returns the churn within the window, accurate to a minute.
+// The window must not exceed the retention set with RetainChurn.
+func (r *Repository) ChurnSince(window time.Duration) map[ChurnKey]Churn {
+	r.rlock()
+	defer r.mu.RUnlock()
+
+	since := time.Now().Add(-window)
+	churn := make(map[ChurnKey]*Churn)
+	for _, bucket := range r.churn.history {
+		if bucket.start.Add(churnResolution).Before(since) {
+			continue
+		}
+		for key, c := range bucket.churn {
+			if churn[key] == nil {
+				churn[key] = &Churn{}
+			}
+			churn[key].add(c)
+		}
+	}
+
+	result := make(map[ChurnKey]Churn, len(churn))
+	for key, c := range churn {
+		result[key] = *c
+	}
+	return result
+}
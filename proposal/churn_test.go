@@ -0,0 +1,119 @@
+package proposal
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	churnDE = ChurnKey{Country: "DE", ServiceType: "wireguard"}
+	churnUS = ChurnKey{Country: "US", ServiceType: "wireguard"}
+)
+
+func churnProposal(id, country string) *Proposal {
+	return &Proposal{ProviderID: id, ServiceType: "wireguard", Location: Location{Country: country}}
+}
+
+func TestChurn(t *testing.T) {
+	// proposals expire immediately, so RemoveExpired removes all of them
+	r := NewProposalRepository(-time.Second)
+	r.RetainChurn(time.Hour)
+
+	a := churnProposal("0xaaa", "DE")
+	r.Store(a)
+	r.Store(a)
+	r.RenewOrStore(churnProposal("0xbbb", "DE"))
+	r.Store(churnProposal("0xccc", "US"))
+	r.Remove(a.ServiceKey())
+	r.Remove(a.ServiceKey())
+	r.RemoveProvider("0xccc")
+	r.RemoveExpired()
+
+	// updates of stored proposals and removals of missing proposals are not counted
+	want := map[ChurnKey]Churn{
+		churnDE: {Arrivals: 2, Unregistrations: 1, Expiries: 1},
+		churnUS: {Arrivals: 1, Evictions: 1},
+	}
+
+	for name, churn := range map[string]map[ChurnKey]Churn{"total": r.Churn(), "window": r.ChurnSince(time.Hour)} {
+		if len(churn) != len(want) {
+			t.Errorf("%s: got %d keys, want %d", name, len(churn), len(want))
+		}
+		for key, c := range want {
+			if churn[key] != c {
+				t.Errorf("%s: churn of %v = %+v, want %+v", name, key, churn[key], c)
+			}
+		}
+	}
+}
+
+// backdate replaces the churn history with buckets of one arrival each, started age ago.
+func backdate(r *Repository, ages ...time.Duration) {
+	now := time.Now()
+	r.churn.history = nil
+	for _, age := range ages {
+		r.churn.history = append(r.churn.history, churnBucket{
+			start: now.Add(-age).Truncate(churnResolution),
+			churn: map[ChurnKey]*Churn{churnDE: {Arrivals: 1}},
+		})
+	}
+}
+
+func TestChurnSince(t *testing.T) {
+	r := NewProposalRepository(time.Hour)
+	r.RetainChurn(2 * time.Hour)
+	backdate(r, 90*time.Minute, 50*time.Minute, 20*time.Minute, 5*time.Minute, 0)
+
+	tests := []struct {
+		window time.Duration
+		want   uint64
+	}{
+		{0, 1},
+		{10 * time.Minute, 2},
+		{30 * time.Minute, 3},
+		{time.Hour, 4},
+		{2 * time.Hour, 5},
+	}
+
+	for _, tt := range tests {
+		if got := r.ChurnSince(tt.window)[churnDE].Arrivals; got != tt.want {
+			t.Errorf("ChurnSince(%s) arrivals = %d, want %d", tt.window, got, tt.want)
+		}
+	}
+
+	// the totals are independent of the history
+	if got := r.Churn()[churnDE]; got != (Churn{}) {
+		t.Errorf("Churn() = %+v, want no churn", got)
+	}
+}
+
+func TestChurnRetention(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention []time.Duration
+		ages      []time.Duration
+		// buckets is the length of the history after one more proposal arrived
+		buckets int
+	}{
+		{"no retention", nil, nil, 0},
+		{"same minute", []time.Duration{time.Hour}, []time.Duration{0}, 1},
+		{"new minute", []time.Duration{time.Hour}, []time.Duration{2 * time.Minute}, 2},
+		{"expired buckets", []time.Duration{time.Hour}, []time.Duration{3 * time.Hour, 2 * time.Hour, 30 * time.Minute}, 2},
+		{"longest retention", []time.Duration{3 * time.Hour, time.Hour}, []time.Duration{2 * time.Hour, 30 * time.Minute}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewProposalRepository(time.Hour)
+			for _, window := range tt.retention {
+				r.RetainChurn(window)
+			}
+			backdate(r, tt.ages...)
+
+			r.Store(churnProposal("0xaaa", "DE"))
+			if got := len(r.churn.history); got != tt.buckets {
+				t.Errorf("history has %d buckets, want %d", got, tt.buckets)
+			}
+		})
+	}
+}
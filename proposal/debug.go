@@ -0,0 +1,76 @@
+package proposal
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+type lockStats struct {
+	acquisitions     atomic.Uint64
+	readAcquisitions atomic.Uint64
+	waitNanos        atomic.Int64
+	readWaitNanos    atomic.Int64
+}
+
+func (r *Repository) lock() {
+	start := time.Now()
+	r.mu.Lock()
+	r.lockStats.acquisitions.Add(1)
+	r.lockStats.waitNanos.Add(int64(time.Since(start)))
+}
+
+func (r *Repository) rlock() {
+	start := time.Now()
+	r.mu.RLock()
+	r.lockStats.readAcquisitions.Add(1)
+	r.lockStats.readWaitNanos.Add(int64(time.Since(start)))
+}
+
+type DebugStats struct {
+	Proposals         int              `json:"proposals"`
+	PinnedProposals   []string         `json:"pinned_proposals"`
+	LockAcquisitions  uint64           `json:"lock_acquisitions"`
+	LockWait          time.Duration    `json:"lock_wait_ns"`
+	RLockAcquisitions uint64           `json:"rlock_acquisitions"`
+	RLockWait         time.Duration    `json:"rlock_wait_ns"`
+	LastEventID       uint64           `json:"last_event_id"`
+	EventHistory      int              `json:"event_history"`
+	Subscribers       []SubscriberStat `json:"subscribers"`
+}
+
+// SubscriberStat is the fill level of the event queue of a subscriber.
+type SubscriberStat struct {
+	Queued   int `json:"queued"`
+	Capacity int `json:"capacity"`
+}
+
+// DebugStats dumps internal counters of the repository, its lock and the event queues.
+func (r *Repository) DebugStats() DebugStats {
+	stats := DebugStats{
+		LockAcquisitions:  r.lockStats.acquisitions.Load(),
+		LockWait:          time.Duration(r.lockStats.waitNanos.Load()),
+		RLockAcquisitions: r.lockStats.readAcquisitions.Load(),
+		RLockWait:         time.Duration(r.lockStats.readWaitNanos.Load()),
+		PinnedProposals:   []string{},
+		Subscribers:       []SubscriberStat{},
+	}
+
+	r.rlock()
+	stats.Proposals = len(r.proposals)
+	for key, rcd := range r.proposals {
+		if rcd.pinned {
+			stats.PinnedProposals = append(stats.PinnedProposals, key)
+		}
+	}
+	r.mu.RUnlock()
+
+	r.events.mu.Lock()
+	stats.LastEventID = r.events.lastID
+	stats.EventHistory = len(r.events.history)
+	for s := range r.events.subscribers {
+		stats.Subscribers = append(stats.Subscribers, SubscriberStat{Queued: len(s.ch), Capacity: cap(s.ch)})
+	}
+	r.events.mu.Unlock()
+
+	return stats
+}
@@ -0,0 +1,178 @@
+package proposal
+
+import (
+	"sync"
+	"time"
+)
+
+const eventHistorySize = 4096
+
+type EventType string
+
+const (
+	EventRegistered      EventType = "registered"
+	EventPingAfterExpiry EventType = "ping_after_expiry"
+	EventUnregistered    EventType = "unregistered"
+	EventExpired         EventType = "expired"
+	EventEvicted         EventType = "evicted"
+	EventQualityChanged  EventType = "quality_changed"
+	// EventReset is sent instead of a replay if the events after the last event ID of a resuming
+	// subscriber are no longer buffered, e.g. because it fell too far behind or propmon restarted.
+	// It carries no proposal, subscribers have to reload the full state.
+	EventReset EventType = "reset"
+)
+
+type Event struct {
+	ID       uint64    `json:"id"`
+	Type     EventType `json:"type"`
+	Time     time.Time `json:"time"`
+	Proposal *Proposal `json:"proposal,omitempty"`
+}
+
+// eventHub fans repository events out to subscribers and keeps a short history so
+// reconnecting subscribers can resume where they left off.
+type eventHub struct {
+	lastID uint64
+	// history is a ring buffer of the last events, the oldest one is at head once it is full
+	history     []Event
+	head        int
+	size        int
+	subscribers map[*Subscription]struct{}
+	mu          sync.Mutex
+}
+
+// Subscription receives repository events on C. If the subscriber does not keep up and its
+// buffer fills, C is closed and Overflowed reports true.
+type Subscription struct {
+	C          <-chan Event
+	ch         chan Event
+	filter     *Filter
+	hub        *eventHub
+	overflowed bool
+	closed     bool
+}
+
+// newEventHub seeds the event IDs with the start time in microseconds, so the IDs of a previous
+// run are lower than every ID of this one and resuming from them is detected as a gap.
+func newEventHub(size int) *eventHub {
+	return &eventHub{
+		lastID:      uint64(time.Now().UnixMicro()),
+		history:     make([]Event, 0, size),
+		size:        size,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+func (h *eventHub) publish(t EventType, p *Proposal) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	e := Event{
+		ID:       h.lastID,
+		Type:     t,
+		Time:     time.Now(),
+		Proposal: p,
+	}
+
+	if len(h.history) < h.size {
+		h.history = append(h.history, e)
+	} else if h.size > 0 {
+		h.history[h.head] = e
+		h.head = (h.head + 1) % h.size
+	}
+
+	for s := range h.subscribers {
+		s.send(e)
+	}
+}
+
+func (h *eventHub) subscribe(filter *Filter, lastID uint64, buffer int) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var replay []Event
+	if lastID > 0 {
+		if h.covers(lastID) {
+			for i := range len(h.history) {
+				e := h.history[(h.head+i)%len(h.history)]
+				if e.ID > lastID && filter.Match(e.Proposal) {
+					replay = append(replay, e)
+				}
+			}
+		} else {
+			replay = append(replay, Event{ID: h.lastID, Type: EventReset, Time: time.Now()})
+		}
+	}
+
+	// the replayed events do not count against the buffer of the subscriber
+	ch := make(chan Event, buffer+len(replay))
+	s := &Subscription{
+		C:      ch,
+		ch:     ch,
+		filter: filter,
+		hub:    h,
+	}
+	h.subscribers[s] = struct{}{}
+
+	for _, e := range replay {
+		s.ch <- e
+	}
+
+	return s
+}
+
+// covers reports whether every event published after lastID is still in the history.
+// It must be called with the hub lock held.
+func (h *eventHub) covers(lastID uint64) bool {
+	if lastID > h.lastID {
+		return false
+	}
+	if len(h.history) == 0 {
+		return lastID == h.lastID
+	}
+	return lastID >= h.history[h.head].ID-1
+}
+
+// send must be called with the hub lock held.
+func (s *Subscription) send(e Event) {
+	if s.closed || !s.filter.Match(e.Proposal) {
+		return
+	}
+
+	select {
+	case s.ch <- e:
+	default:
+		s.overflowed = true
+		s.close()
+	}
+}
+
+// close must be called with the hub lock held.
+func (s *Subscription) close() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.ch)
+	delete(s.hub.subscribers, s)
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.close()
+}
+
+func (s *Subscription) Overflowed() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.overflowed
+}
+
+// Subscribe returns a subscription to repository events matching the filter. If lastID is set,
+// buffered events published after it are replayed first, or a reset event is sent if some of
+// them are no longer buffered.
+func (r *Repository) Subscribe(filter *Filter, lastID uint64, buffer int) *Subscription {
+	return r.events.subscribe(filter, lastID, buffer)
+}
@@ -0,0 +1,109 @@
+package proposal
+
+import (
+	"slices"
+	"testing"
+)
+
+func publishN(h *eventHub, n int) {
+	for range n {
+		h.publish(EventRegistered, testProposal())
+	}
+}
+
+func receive(s *Subscription) []Event {
+	var events []Event
+	for {
+		select {
+		case e := <-s.C:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func eventIDs(events []Event) []uint64 {
+	ids := make([]uint64, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestEventHistoryRing(t *testing.T) {
+	h := newEventHub(3)
+	start := h.lastID
+	publishN(h, 5)
+
+	s := h.subscribe(nil, start+2, 10)
+	defer s.Close()
+
+	got := receive(s)
+	if want := []uint64{start + 3, start + 4, start + 5}; !slices.Equal(eventIDs(got), want) {
+		t.Errorf("replayed %v, want %v", eventIDs(got), want)
+	}
+}
+
+func TestEventReplay(t *testing.T) {
+	h := newEventHub(3)
+	start := h.lastID
+	publishN(h, 5)
+
+	tests := []struct {
+		name   string
+		lastID uint64
+		want   []uint64
+		reset  bool
+	}{
+		{"no last id", 0, nil, false},
+		{"up to date", start + 5, nil, false},
+		{"partial", start + 4, []uint64{start + 5}, false},
+		{"oldest buffered", start + 2, []uint64{start + 3, start + 4, start + 5}, false},
+		{"aged out", start + 1, []uint64{start + 5}, true},
+		{"previous run", start - 100, []uint64{start + 5}, true},
+		{"unknown", start + 6, []uint64{start + 5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := h.subscribe(nil, tt.lastID, 10)
+			defer s.Close()
+
+			got := receive(s)
+			if !slices.Equal(eventIDs(got), tt.want) {
+				t.Errorf("replayed %v, want %v", eventIDs(got), tt.want)
+			}
+			if reset := len(got) > 0 && got[0].Type == EventReset; reset != tt.reset {
+				t.Errorf("reset = %v, want %v", reset, tt.reset)
+			}
+		})
+	}
+}
+
+func TestEventResetWithoutHistory(t *testing.T) {
+	h := newEventHub(3)
+
+	// the ids of a previous run are lower than the seed of a new hub
+	s := h.subscribe(nil, h.lastID-1, 10)
+	defer s.Close()
+
+	got := receive(s)
+	if len(got) != 1 || got[0].Type != EventReset || got[0].ID != h.lastID {
+		t.Errorf("got %+v, want a single reset event with the current id", got)
+	}
+}
+
+func TestSubscriptionOverflow(t *testing.T) {
+	h := newEventHub(3)
+	s := h.subscribe(nil, 0, 1)
+	publishN(h, 2)
+
+	if !s.Overflowed() {
+		t.Error("subscription did not overflow")
+	}
+	<-s.C
+	if _, ok := <-s.C; ok {
+		t.Error("channel of an overflowed subscription is still open")
+	}
+}
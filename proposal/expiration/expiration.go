@@ -15,6 +15,7 @@ type Service struct {
 	interval   time.Duration
 	stopCh     chan struct{}
 	waitGroup  sync.WaitGroup
+	sweepMu    sync.Mutex
 }
 
 func NewExpirationService(repository *proposal.Repository, interval time.Duration) *Service {
@@ -36,20 +37,32 @@ func (e *Service) Stop() {
 	e.waitGroup.Wait()
 }
 
+// Sweep immediately removes expired proposals and returns how many expired.
+func (e *Service) Sweep() int {
+	e.sweepMu.Lock()
+	defer e.sweepMu.Unlock()
+
+	expired := e.repository.RemoveExpired()
+	metrics.ProposalsExpired(expired)
+
+	return expired
+}
+
 func (e *Service) run() {
 	defer e.waitGroup.Done()
 
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-e.stopCh:
 			return
 
-		default:
+		case <-ticker.C:
 			if e.repository.CountProposals() > 0 {
-				expired := e.repository.RemoveExpired()
-				metrics.UpdateMetrics(e.repository, expired)
+				e.Sweep()
 			}
-			time.Sleep(e.interval)
 		}
 	}
 }
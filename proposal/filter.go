@@ -0,0 +1,383 @@
+package proposal
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type Field string
+
+const (
+	FieldProviderID         Field = "id"
+	FieldServiceType        Field = "service"
+	FieldContinent          Field = "continent"
+	FieldCountry            Field = "country"
+	FieldRegion             Field = "region"
+	FieldCity               Field = "city"
+	FieldAsn                Field = "asn"
+	FieldIsp                Field = "isp"
+	FieldIpType             Field = "type"
+	FieldAccessPolicyID     Field = "access_policy"
+	FieldAccessPolicySource Field = "access_policy_source"
+	FieldHasAccessPolicy    Field = "has_access_policy"
+	FieldCompatibility      Field = "compatibility"
+	FieldHasQuality         Field = "has_quality"
+	FieldQuality            Field = "quality"
+	FieldLatency            Field = "latency"
+	FieldBandwidth          Field = "bandwidth"
+	FieldUptime             Field = "uptime"
+)
+
+type Operator string
+
+const (
+	OpEqual    Operator = "="
+	OpNotEqual Operator = "!="
+	OpMin      Operator = ">="
+	OpMax      Operator = "<="
+)
+
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindNumber
+	kindBool
+)
+
+type fieldSpec struct {
+	kind fieldKind
+	// text fields may hold several values per proposal, a comparison matches if any of them does
+	values func(p *Proposal) []string
+	// foldCase compares human readable text fields case-insensitively, identifiers are compared exactly
+	foldCase bool
+	number   func(p *Proposal) (float64, bool)
+	flag     func(p *Proposal) bool
+}
+
+var fields = map[Field]fieldSpec{
+	FieldProviderID:  stringField(func(p *Proposal) string { return p.ProviderID }),
+	FieldServiceType: stringField(func(p *Proposal) string { return p.ServiceType }),
+	FieldContinent:   textField(func(p *Proposal) string { return p.Location.Continent }),
+	FieldCountry:     textField(func(p *Proposal) string { return p.Location.Country }),
+	FieldRegion:      textField(func(p *Proposal) string { return p.Location.Region }),
+	FieldCity:        textField(func(p *Proposal) string { return p.Location.City }),
+	FieldIsp:         textField(func(p *Proposal) string { return p.Location.Isp }),
+	FieldIpType:      textField(func(p *Proposal) string { return p.Location.IpType }),
+	FieldAsn: numberField(func(p *Proposal) (float64, bool) {
+		return float64(p.Location.Asn), true
+	}),
+	FieldAccessPolicyID: {kind: kindString, values: func(p *Proposal) []string {
+		ids := make([]string, len(p.AccessPolicies))
+		for i, policy := range p.AccessPolicies {
+			ids[i] = policy.ID
+		}
+		return ids
+	}},
+	FieldAccessPolicySource: {kind: kindString, values: func(p *Proposal) []string {
+		sources := make([]string, len(p.AccessPolicies))
+		for i, policy := range p.AccessPolicies {
+			sources[i] = policy.Source
+		}
+		return sources
+	}},
+	FieldHasAccessPolicy: {kind: kindBool, flag: func(p *Proposal) bool { return len(p.AccessPolicies) > 0 }},
+	FieldCompatibility: numberField(func(p *Proposal) (float64, bool) {
+		return float64(p.Compatibility), true
+	}),
+	FieldHasQuality: {kind: kindBool, flag: func(p *Proposal) bool { return p.Quality != nil }},
+	FieldQuality:    qualityField(func(q *Quality) float64 { return q.Quality }),
+	FieldLatency:    qualityField(func(q *Quality) float64 { return q.Latency }),
+	FieldBandwidth:  qualityField(func(q *Quality) float64 { return q.Bandwidth }),
+	FieldUptime:     qualityField(func(q *Quality) float64 { return q.Uptime }),
+}
+
+func stringField(value func(p *Proposal) string) fieldSpec {
+	return fieldSpec{kind: kindString, values: func(p *Proposal) []string { return []string{value(p)} }}
+}
+
+func textField(value func(p *Proposal) string) fieldSpec {
+	spec := stringField(value)
+	spec.foldCase = true
+	return spec
+}
+
+func numberField(value func(p *Proposal) (float64, bool)) fieldSpec {
+	return fieldSpec{kind: kindNumber, number: value}
+}
+
+func qualityField(value func(q *Quality) float64) fieldSpec {
+	return numberField(func(p *Proposal) (float64, bool) {
+		if p.Quality == nil {
+			return 0, false
+		}
+		return value(p.Quality), true
+	})
+}
+
+// Condition compares a proposal field against one or more values.
+// Equality conditions match if the field equals any of the values.
+type Condition struct {
+	Field    Field
+	Operator Operator
+	Values   []string
+}
+
+type condition struct {
+	Condition
+	match func(p *Proposal) bool
+}
+
+// Filter is a conjunction of conditions. A nil or empty filter matches every proposal.
+type Filter struct {
+	conditions []condition
+}
+
+func NewFilter() *Filter {
+	return &Filter{}
+}
+
+// ParseFilter builds a filter from url query parameters such as
+// country=DE,FR, country!=US, quality_min=1.5 or has_quality=true.
+// Commas inside a value are escaped as \, and backslashes as \\.
+// Parameters that do not name a filter field are ignored.
+func ParseFilter(query url.Values) (*Filter, error) {
+	filter := NewFilter()
+
+	for key, values := range query {
+		field, op := parseFilterKey(key)
+		if _, ok := fields[field]; !ok {
+			continue
+		}
+
+		var split []string
+		for _, value := range values {
+			for _, v := range splitValues(value) {
+				if v = strings.TrimSpace(v); v != "" {
+					split = append(split, v)
+				}
+			}
+		}
+		if len(split) == 0 {
+			continue
+		}
+
+		if err := filter.Where(field, op, split...); err != nil {
+			return nil, err
+		}
+	}
+
+	return filter, nil
+}
+
+func parseFilterKey(key string) (Field, Operator) {
+	switch {
+	case strings.HasSuffix(key, "!"):
+		return Field(strings.TrimSuffix(key, "!")), OpNotEqual
+	case strings.HasSuffix(key, "_min"):
+		return Field(strings.TrimSuffix(key, "_min")), OpMin
+	case strings.HasSuffix(key, "_max"):
+		return Field(strings.TrimSuffix(key, "_max")), OpMax
+	default:
+		return Field(key), OpEqual
+	}
+}
+
+// Where adds a condition to the filter.
+func (f *Filter) Where(field Field, op Operator, values ...string) error {
+	spec, ok := fields[field]
+	if !ok {
+		return fmt.Errorf("unknown filter field %q", field)
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("filter %s%s requires a value", field, op)
+	}
+
+	var match func(p *Proposal) bool
+	var err error
+
+	switch spec.kind {
+	case kindString:
+		match, err = stringCondition(spec, op, values)
+	case kindNumber:
+		match, err = numberCondition(spec, op, values)
+	case kindBool:
+		match, err = boolCondition(spec, op, values)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid filter %s%s: %w", field, op, err)
+	}
+
+	f.conditions = append(f.conditions, condition{
+		Condition: Condition{Field: field, Operator: op, Values: values},
+		match:     match,
+	})
+
+	return nil
+}
+
+func (f *Filter) Conditions() []Condition {
+	if f == nil {
+		return nil
+	}
+
+	conditions := make([]Condition, len(f.conditions))
+	for i, c := range f.conditions {
+		conditions[i] = c.Condition
+	}
+
+	return conditions
+}
+
+// Values encodes the filter as url query parameters understood by ParseFilter.
+func (f *Filter) Values() url.Values {
+	query := url.Values{}
+
+	for _, c := range f.Conditions() {
+		key := string(c.Field)
+		switch c.Operator {
+		case OpNotEqual:
+			key += "!"
+		case OpMin:
+			key += "_min"
+		case OpMax:
+			key += "_max"
+		}
+		escaped := make([]string, len(c.Values))
+		for i, v := range c.Values {
+			escaped[i] = escapeValue(v)
+		}
+		query.Add(key, strings.Join(escaped, ","))
+	}
+
+	return query
+}
+
+var valueEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`)
+
+func escapeValue(v string) string {
+	return valueEscaper.Replace(v)
+}
+
+// splitValues splits a comma separated list of values, commas and backslashes are escaped with a backslash.
+func splitValues(s string) []string {
+	var values []string
+	var value strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+			value.WriteByte(s[i])
+		case s[i] == ',':
+			values = append(values, value.String())
+			value.Reset()
+		default:
+			value.WriteByte(s[i])
+		}
+	}
+	return append(values, value.String())
+}
+
+func (f *Filter) Match(p *Proposal) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, c := range f.conditions {
+		if !c.match(p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func stringCondition(spec fieldSpec, op Operator, values []string) (func(p *Proposal) bool, error) {
+	contains := func(p *Proposal) bool {
+		for _, v := range spec.values(p) {
+			if slices.ContainsFunc(values, func(value string) bool {
+				if spec.foldCase {
+					return strings.EqualFold(v, value)
+				}
+				return v == value
+			}) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch op {
+	case OpEqual:
+		return contains, nil
+	case OpNotEqual:
+		return func(p *Proposal) bool { return !contains(p) }, nil
+	default:
+		return nil, fmt.Errorf("operator not supported for text fields")
+	}
+}
+
+func numberCondition(spec fieldSpec, op Operator, values []string) (func(p *Proposal) bool, error) {
+	numbers := make([]float64, len(values))
+	for i, value := range values {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		numbers[i] = n
+	}
+
+	switch op {
+	case OpEqual, OpNotEqual:
+		want := op == OpEqual
+		return func(p *Proposal) bool {
+			v, ok := spec.number(p)
+			if !ok {
+				return !want
+			}
+			return slices.Contains(numbers, v) == want
+		}, nil
+
+	case OpMin, OpMax:
+		if len(numbers) != 1 {
+			return nil, fmt.Errorf("expected a single value")
+		}
+		bound := numbers[0]
+		return func(p *Proposal) bool {
+			v, ok := spec.number(p)
+			if !ok {
+				return false
+			}
+			if op == OpMin {
+				return v >= bound
+			}
+			return v <= bound
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown operator")
+}
+
+func boolCondition(spec fieldSpec, op Operator, values []string) (func(p *Proposal) bool, error) {
+	if len(values) != 1 {
+		return nil, fmt.Errorf("expected a single value")
+	}
+
+	want, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a boolean", values[0])
+	}
+
+	switch op {
+	case OpEqual:
+	case OpNotEqual:
+		want = !want
+	default:
+		return nil, fmt.Errorf("operator not supported for boolean fields")
+	}
+
+	return func(p *Proposal) bool { return spec.flag(p) == want }, nil
+}
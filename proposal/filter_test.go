@@ -0,0 +1,140 @@
+package proposal
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func testProposal() *Proposal {
+	return &Proposal{
+		ProviderID:    "0xabc",
+		ServiceType:   "wireguard",
+		Compatibility: 2,
+		Location: Location{
+			Continent: "EU",
+			Country:   "DE",
+			City:      "Berlin",
+			Asn:       3320,
+			Isp:       "Example, Inc.",
+			IpType:    "residential",
+		},
+		AccessPolicies: []AccessPolicy{{ID: "mysterium", Source: "https://trust.mysterium.network"}},
+		Quality:        &Quality{Quality: 2.5, Latency: 40, Bandwidth: 80, Uptime: 20},
+	}
+}
+
+func TestParseFilterMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		match bool
+	}{
+		{"empty", "", true},
+		{"equal", "country=DE", true},
+		{"any of", "country=FR,DE", true},
+		{"none of", "country=FR,US", false},
+		{"not equal", "country!=DE", false},
+		{"country case-insensitive", "country=de", true},
+		{"node type case-insensitive", "type=Residential", true},
+		{"id exact", "id=0xabc", true},
+		{"id case-sensitive", "id=0xABC", false},
+		{"service case-sensitive", "service=WireGuard", false},
+		{"escaped comma", `isp=Example\, Inc.`, true},
+		{"unescaped comma splits", "isp=Example, Inc.", false},
+		{"number equal", "asn=3320", true},
+		{"number min", "quality_min=2", true},
+		{"number max", "latency_max=30", false},
+		{"compatibility range", "compatibility_min=1&compatibility_max=2", true},
+		{"bool", "has_quality=true", true},
+		{"negated bool", "has_access_policy!=true", false},
+		{"access policy", "access_policy=mysterium", true},
+		{"unknown parameter ignored", "max=10&country=DE", true},
+		{"conjunction", "country=DE&service=openvpn", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			filter, err := ParseFilter(query)
+			if err != nil {
+				t.Fatalf("ParseFilter(%q) failed: %v", tt.query, err)
+			}
+			if got := filter.Match(testProposal()); got != tt.match {
+				t.Errorf("Match = %v, want %v", got, tt.match)
+			}
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []string{
+		"asn=abc",
+		"quality_min=1,2",
+		"has_quality=maybe",
+		"country_min=DE",
+		"has_quality_min=true",
+	}
+
+	for _, query := range tests {
+		t.Run(query, func(t *testing.T) {
+			values, err := url.ParseQuery(query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ParseFilter(values); err == nil {
+				t.Errorf("ParseFilter(%q) succeeded, want an error", query)
+			}
+		})
+	}
+}
+
+func TestFilterValuesRoundTrip(t *testing.T) {
+	filter := NewFilter()
+	if err := filter.Where(FieldIsp, OpEqual, "Example, Inc.", `back\slash`); err != nil {
+		t.Fatal(err)
+	}
+	if err := filter.Where(FieldQuality, OpMin, "1.5"); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseFilter(filter.Values())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := filter.Conditions()
+	got := parsed.Conditions()
+	slices.SortFunc(got, func(a, b Condition) int { return len(a.Field) - len(b.Field) })
+	slices.SortFunc(want, func(a, b Condition) int { return len(a.Field) - len(b.Field) })
+	if len(got) != len(want) {
+		t.Fatalf("got %d conditions, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Field != want[i].Field || got[i].Operator != want[i].Operator || !slices.Equal(got[i].Values, want[i].Values) {
+			t.Errorf("condition %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSplitValues(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"a", []string{"a"}},
+		{"a,b", []string{"a", "b"}},
+		{`a\,b`, []string{"a,b"}},
+		{`a\\,b`, []string{`a\`, "b"}},
+		{`a\`, []string{`a\`}},
+	}
+
+	for _, tt := range tests {
+		if got := splitValues(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("splitValues(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
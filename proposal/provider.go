@@ -1,5 +1,11 @@
 package proposal
 
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
 type Provider struct {
 	ID       string
 	Location Location
@@ -13,3 +19,107 @@ type Service struct {
 	Contacts       []Contact
 	AccessPolicies []AccessPolicy
 }
+
+func newService(p *Proposal) Service {
+	return Service{
+		ServiceType:    p.ServiceType,
+		Compatibility:  p.Compatibility,
+		Contacts:       p.Contacts,
+		AccessPolicies: p.AccessPolicies,
+	}
+}
+
+// MatchingProviders returns the providers with at least one proposal matching the filter, ordered by ID.
+// The providers list all of their services, not only the matching ones.
+func (r *Repository) MatchingProviders(filter *Filter) []*Provider {
+	r.rlock()
+	defer r.mu.RUnlock()
+
+	matching := make(map[string]struct{})
+	for _, rcd := range r.proposals {
+		if filter.Match(rcd.proposal) {
+			matching[rcd.proposal.ProviderID] = struct{}{}
+		}
+	}
+
+	providers := make(map[string]*Provider, len(matching))
+	for _, rcd := range r.proposals {
+		p := rcd.proposal
+		if _, ok := matching[p.ProviderID]; !ok {
+			continue
+		}
+
+		provider, ok := providers[p.ProviderID]
+		if !ok {
+			provider = &Provider{ID: p.ProviderID, Location: p.Location, Quality: p.Quality}
+			providers[p.ProviderID] = provider
+		}
+		provider.Services = append(provider.Services, newService(p))
+	}
+
+	sorted := make([]*Provider, 0, len(providers))
+	for _, provider := range providers {
+		slices.SortFunc(provider.Services, func(a, b Service) int { return strings.Compare(a.ServiceType, b.ServiceType) })
+		sorted = append(sorted, provider)
+	}
+	slices.SortFunc(sorted, func(a, b *Provider) int { return strings.Compare(a.ID, b.ID) })
+
+	return sorted
+}
+
+// Provider returns the provider with all of its services or nil if it has no proposals.
+func (r *Repository) Provider(id string) *Provider {
+	filter := NewFilter()
+	if err := filter.Where(FieldProviderID, OpEqual, id); err != nil {
+		return nil
+	}
+
+	providers := r.MatchingProviders(filter)
+	if len(providers) == 0 {
+		return nil
+	}
+	return providers[0]
+}
+
+// ProviderActivity describes when a provider was last seen.
+type ProviderActivity struct {
+	Proposals int
+	LastSeen  time.Time
+	Quality   *Quality
+}
+
+// Activity returns the activity of the given providers. Providers without proposals are omitted.
+func (r *Repository) Activity(ids []string) map[string]*ProviderActivity {
+	r.rlock()
+	defer r.mu.RUnlock()
+
+	activity := make(map[string]*ProviderActivity, len(ids))
+	for _, id := range ids {
+		activity[id] = nil
+	}
+
+	for _, rcd := range r.proposals {
+		id := rcd.proposal.ProviderID
+		a, ok := activity[id]
+		if !ok {
+			continue
+		}
+		if a == nil {
+			a = &ProviderActivity{Quality: rcd.proposal.Quality}
+			activity[id] = a
+		}
+
+		a.Proposals++
+		if rcd.lastSeen.After(a.LastSeen) {
+			a.LastSeen = rcd.lastSeen
+		}
+	}
+
+	for id, a := range activity {
+		if a == nil {
+			delete(activity, id)
+		}
+	}
+
+	return activity
+}
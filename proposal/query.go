@@ -0,0 +1,211 @@
+package proposal
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type SortField string
+
+const (
+	SortQuality    SortField = "quality"
+	SortLatency    SortField = "latency"
+	SortBandwidth  SortField = "bandwidth"
+	SortUptime     SortField = "uptime"
+	SortFirstSeen  SortField = "first_seen"
+	SortProviderID SortField = "provider_id"
+)
+
+type Sort struct {
+	Field      SortField
+	Descending bool
+}
+
+var DefaultSort = Sort{Field: SortProviderID}
+
+// ParseSort parses a sort field name, optionally prefixed with "-" for descending order.
+func ParseSort(s string) (Sort, error) {
+	if s == "" {
+		return DefaultSort, nil
+	}
+
+	sort := Sort{Field: SortField(strings.TrimPrefix(s, "-")), Descending: strings.HasPrefix(s, "-")}
+	switch sort.Field {
+	case SortQuality, SortLatency, SortBandwidth, SortUptime, SortFirstSeen, SortProviderID:
+		return sort, nil
+	default:
+		return Sort{}, fmt.Errorf("unknown sort field %q", sort.Field)
+	}
+}
+
+func (s Sort) String() string {
+	if s.Descending {
+		return "-" + string(s.Field)
+	}
+	return string(s.Field)
+}
+
+type Query struct {
+	Filter *Filter
+	Sort   Sort
+	// Cursor continues a previous query after its last result
+	Cursor string
+	Limit  int
+}
+
+// sortKey is the position of a proposal in a sorted listing.
+// Proposals without a value for the sort field are always listed last.
+type sortKey struct {
+	Num     float64 `json:"n,omitempty"`
+	Str     string  `json:"s,omitempty"`
+	Missing bool    `json:"m,omitempty"`
+	Key     string  `json:"k"`
+}
+
+type cursor struct {
+	Sort string  `json:"o"`
+	Last sortKey `json:"l"`
+}
+
+type sortEntry struct {
+	key      sortKey
+	proposal *Proposal
+}
+
+// Query returns the proposals matching the query in a stable order and a cursor for the next page.
+// The cursor is empty if there are no further results. Because cursors point between positions of
+// the sort order rather than at an offset, pages stay consistent when proposals come and go.
+func (r *Repository) Query(q Query) ([]*Proposal, string, error) {
+	if q.Sort.Field == "" {
+		q.Sort = DefaultSort
+	}
+
+	var after *sortKey
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if c.Sort != q.Sort.String() {
+			return nil, "", fmt.Errorf("cursor was created for sort order %q", c.Sort)
+		}
+		after = &c.Last
+	}
+
+	var entries []sortEntry
+
+	r.rlock()
+	for key, rcd := range r.proposals {
+		if !q.Filter.Match(rcd.proposal) {
+			continue
+		}
+		entries = append(entries, sortEntry{key: rcd.sortKey(key, q.Sort.Field), proposal: rcd.proposal})
+	}
+	r.mu.RUnlock()
+
+	compare := func(a, b sortKey) int {
+		return compareSortKeys(a, b, q.Sort.Descending)
+	}
+	slices.SortFunc(entries, func(a, b sortEntry) int { return compare(a.key, b.key) })
+
+	start := 0
+	if after != nil {
+		start, _ = slices.BinarySearchFunc(entries, *after, func(e sortEntry, target sortKey) int {
+			if compare(e.key, target) <= 0 {
+				return -1
+			}
+			return 1
+		})
+	}
+
+	end := len(entries)
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+	}
+
+	proposals := make([]*Proposal, 0, end-start)
+	for _, e := range entries[start:end] {
+		proposals = append(proposals, e.proposal)
+	}
+
+	var next string
+	if end < len(entries) && end > start {
+		next = encodeCursor(cursor{Sort: q.Sort.String(), Last: entries[end-1].key})
+	}
+
+	return proposals, next, nil
+}
+
+func (rcd proposalRecord) sortKey(key string, field SortField) sortKey {
+	k := sortKey{Key: key}
+	p := rcd.proposal
+
+	switch field {
+	case SortProviderID:
+		k.Str = p.ProviderID
+	case SortFirstSeen:
+		k.Num = float64(rcd.firstSeen.UnixNano())
+	default:
+		if p.Quality == nil {
+			k.Missing = true
+			break
+		}
+		switch field {
+		case SortQuality:
+			k.Num = p.Quality.Quality
+		case SortLatency:
+			k.Num = p.Quality.Latency
+		case SortBandwidth:
+			k.Num = p.Quality.Bandwidth
+		case SortUptime:
+			k.Num = p.Quality.Uptime
+		}
+	}
+
+	return k
+}
+
+func compareSortKeys(a, b sortKey, descending bool) int {
+	if a.Missing != b.Missing {
+		if a.Missing {
+			return 1
+		}
+		return -1
+	}
+
+	c := cmp.Compare(a.Num, b.Num)
+	if c == 0 {
+		c = strings.Compare(a.Str, b.Str)
+	}
+	if descending {
+		c = -c
+	}
+	if c == 0 {
+		c = strings.Compare(a.Key, b.Key)
+	}
+
+	return c
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return c, nil
+}
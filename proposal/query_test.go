@@ -0,0 +1,208 @@
+package proposal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newTestRepository(proposals ...*Proposal) *Repository {
+	r := NewProposalRepository(time.Hour)
+	for _, p := range proposals {
+		r.Store(p)
+	}
+	return r
+}
+
+func qualityProposal(id string, quality *Quality) *Proposal {
+	return &Proposal{ProviderID: id, ServiceType: "wireguard", Quality: quality}
+}
+
+func providerIDs(proposals []*Proposal) []string {
+	ids := make([]string, len(proposals))
+	for i, p := range proposals {
+		ids[i] = p.ProviderID
+	}
+	return ids
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Sort
+		wantErr bool
+	}{
+		{"", DefaultSort, false},
+		{"quality", Sort{Field: SortQuality}, false},
+		{"-latency", Sort{Field: SortLatency, Descending: true}, false},
+		{"first_seen", Sort{Field: SortFirstSeen}, false},
+		{"-provider_id", Sort{Field: SortProviderID, Descending: true}, false},
+		{"country", Sort{}, true},
+		{"--quality", Sort{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSort(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSort(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSort(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if !tt.wantErr && tt.in != "" && got.String() != tt.in {
+			t.Errorf("Sort.String() = %q, want %q", got.String(), tt.in)
+		}
+	}
+}
+
+func TestQuerySortOrder(t *testing.T) {
+	r := newTestRepository(
+		qualityProposal("c", &Quality{Quality: 1}),
+		qualityProposal("a", &Quality{Quality: 2}),
+		qualityProposal("d", nil),
+		qualityProposal("b", &Quality{Quality: 2}),
+	)
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"provider_id", []string{"a", "b", "c", "d"}},
+		{"-provider_id", []string{"d", "c", "b", "a"}},
+		// equal values are ordered by key and proposals without quality come last in both directions
+		{"quality", []string{"c", "a", "b", "d"}},
+		{"-quality", []string{"a", "b", "c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			sort, err := ParseSort(tt.sort)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proposals, next, err := r.Query(Query{Sort: sort})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next != "" {
+				t.Errorf("next = %q, want no cursor", next)
+			}
+			if got := providerIDs(proposals); !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryPagination(t *testing.T) {
+	r := newTestRepository()
+	for i := range 10 {
+		r.Store(qualityProposal(fmt.Sprintf("p%02d", i), &Quality{Quality: float64(i % 3)}))
+	}
+
+	for _, sortName := range []string{"provider_id", "-quality", "first_seen"} {
+		t.Run(sortName, func(t *testing.T) {
+			sort, err := ParseSort(sortName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			all, _, err := r.Query(Query{Sort: sort})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var paged []*Proposal
+			var cursor string
+			for page := 0; ; page++ {
+				proposals, next, err := r.Query(Query{Sort: sort, Cursor: cursor, Limit: 3})
+				if err != nil {
+					t.Fatal(err)
+				}
+				paged = append(paged, proposals...)
+				if next == "" {
+					break
+				}
+				if page > 10 {
+					t.Fatal("pagination does not terminate")
+				}
+				cursor = next
+			}
+
+			if got, want := providerIDs(paged), providerIDs(all); !slices.Equal(got, want) {
+				t.Errorf("paged = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestQueryCursorStableUnderChanges(t *testing.T) {
+	r := newTestRepository()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		r.Store(qualityProposal(id, nil))
+	}
+
+	first, next, err := r.Query(Query{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// removing an already listed proposal and adding one before the cursor must not shift the next page
+	r.Remove("a.wireguard")
+	r.Store(qualityProposal("0", nil))
+
+	second, _, err := r.Query(Query{Cursor: next, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := providerIDs(first); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("first page = %v", got)
+	}
+	if got := providerIDs(second); !slices.Equal(got, []string{"c", "d"}) {
+		t.Errorf("second page = %v, want [c d]", got)
+	}
+}
+
+func TestQueryCursorErrors(t *testing.T) {
+	r := newTestRepository(qualityProposal("a", nil), qualityProposal("b", nil))
+
+	_, next, err := r.Query(Query{Limit: 1})
+	if err != nil || next == "" {
+		t.Fatalf("Query = %q, %v", next, err)
+	}
+
+	tests := []struct {
+		name string
+		q    Query
+	}{
+		{"invalid base64", Query{Cursor: "!!!"}},
+		{"invalid json", Query{Cursor: encodeCursorString("{")}},
+		{"other sort order", Query{Cursor: next, Sort: Sort{Field: SortQuality}}},
+	}
+
+	for _, tt := range tests {
+		if _, _, err := r.Query(tt.q); err == nil {
+			t.Errorf("%s: Query succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := cursor{Sort: "-quality", Last: sortKey{Num: 1.5, Key: "a.wireguard"}}
+
+	got, err := decodeCursor(encodeCursor(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("decodeCursor(encodeCursor(c)) = %+v, want %+v", got, want)
+	}
+}
+
+func encodeCursorString(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
@@ -8,40 +8,88 @@ import (
 type Repository struct {
 	proposalLifetime time.Duration
 	proposals        map[string]proposalRecord
+	events           *eventHub
+	churn            *churnTracker
+	generation       Generation
+	lockStats        lockStats
 	mu               sync.RWMutex
 }
 
 type proposalRecord struct {
-	proposal *Proposal
-	expires  time.Time
+	proposal  *Proposal
+	expires   time.Time
+	firstSeen time.Time
+	lastSeen  time.Time
+	// pinned records never expire
+	pinned bool
 }
 
 func NewProposalRepository(proposalLifetime time.Duration) *Repository {
+	now := time.Now()
 	return &Repository{
 		proposalLifetime: proposalLifetime,
 		proposals:        make(map[string]proposalRecord),
+		events:           newEventHub(eventHistorySize),
+		churn:            newChurnTracker(),
+		generation:       Generation{ID: uint64(now.UnixMicro()), Modified: now},
 	}
 }
 
+// Generation identifies a state of the repository contents.
+type Generation struct {
+	// ID is incremented by every change. Like event ids, it starts at the creation time of the
+	// repository, so generations of different processes do not collide.
+	ID       uint64
+	Modified time.Time
+}
+
+func (r *Repository) Generation() Generation {
+	r.rlock()
+	defer r.mu.RUnlock()
+	return r.generation
+}
+
+// changed must be called with the write lock held.
+func (r *Repository) changed(t EventType, p *Proposal) {
+	r.generation.ID++
+	r.generation.Modified = time.Now()
+	r.events.publish(t, p)
+}
+
 func (r *Repository) Store(p *Proposal) {
-	r.mu.Lock()
+	r.lock()
 	defer r.mu.Unlock()
 
-	r.proposals[p.ServiceKey()] = proposalRecord{
-		proposal: p,
-		expires:  time.Now().Add(r.proposalLifetime),
+	if r.store(p) {
+		r.churn.arrived(p)
 	}
+	r.changed(EventRegistered, p)
+}
+
+// store stores the proposal and reports whether it is new.
+func (r *Repository) store(p *Proposal) bool {
+	now := time.Now()
+	rcd, ok := r.proposals[p.ServiceKey()]
+	if !ok {
+		rcd.firstSeen = now
+	}
+
+	rcd.proposal = p
+	rcd.expires = now.Add(r.proposalLifetime)
+	rcd.lastSeen = now
+	r.proposals[p.ServiceKey()] = rcd
+	return !ok
 }
 
 func (r *Repository) Get(key string) *Proposal {
-	r.mu.RLock()
+	r.rlock()
 	defer r.mu.RUnlock()
 
 	return r.proposals[key].proposal
 }
 
 func (r *Repository) Exists(key string) bool {
-	r.mu.RLock()
+	r.rlock()
 	defer r.mu.RUnlock()
 
 	if _, ok := r.proposals[key]; ok {
@@ -52,36 +100,85 @@ func (r *Repository) Exists(key string) bool {
 }
 
 func (r *Repository) Remove(key string) {
-	r.mu.Lock()
+	r.lock()
 	defer r.mu.Unlock()
 
+	rcd, ok := r.proposals[key]
+	if !ok {
+		return
+	}
+
 	delete(r.proposals, key)
+	r.churn.unregistered(rcd.proposal)
+	r.changed(EventUnregistered, rcd.proposal)
+}
+
+// RemoveProvider removes all proposals of a provider and returns how many were removed.
+func (r *Repository) RemoveProvider(id string) int {
+	r.lock()
+	defer r.mu.Unlock()
+	var removed int
+
+	for key, rcd := range r.proposals {
+		if rcd.proposal.ProviderID == id {
+			delete(r.proposals, key)
+			r.churn.evicted(rcd.proposal)
+			r.changed(EventEvicted, rcd.proposal)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+// SetPinned pins or unpins a proposal. Pinned proposals never expire.
+// It reports whether the proposal exists.
+func (r *Repository) SetPinned(key string, pinned bool) bool {
+	r.lock()
+	defer r.mu.Unlock()
+
+	rcd, ok := r.proposals[key]
+	if !ok {
+		return false
+	}
+
+	rcd.pinned = pinned
+	r.proposals[key] = rcd
+	return true
 }
 
 func (r *Repository) Renew(id string) {
-	r.mu.Lock()
+	r.lock()
 	defer r.mu.Unlock()
 
 	rcd := r.proposals[id]
-	rcd.expires = time.Now().Add(r.proposalLifetime)
+	rcd.lastSeen = time.Now()
+	rcd.expires = rcd.lastSeen.Add(r.proposalLifetime)
 	r.proposals[id] = rcd
 }
 
 func (r *Repository) RenewOrStore(p *Proposal) {
+	r.lock()
+	defer r.mu.Unlock()
+
 	id := p.ServiceKey()
 
-	if r.Exists(id) {
-		r.Renew(id)
-	} else {
-		r.Store(p)
+	if rcd, ok := r.proposals[id]; ok {
+		rcd.lastSeen = time.Now()
+		rcd.expires = rcd.lastSeen.Add(r.proposalLifetime)
+		r.proposals[id] = rcd
+		return
 	}
+
+	r.store(p)
+	r.churn.arrived(p)
+	r.changed(EventPingAfterExpiry, p)
 }
 
 func (r *Repository) Proposals() []*Proposal {
-	r.mu.RLock()
+	r.rlock()
 	defer r.mu.RUnlock()
-	// TODO: create fixed size array once null pointer dereference in metrics.ActiveProposals is fixed
-	var proposals []*Proposal
+	proposals := make([]*Proposal, 0, len(r.proposals))
 
 	for _, rcd := range r.proposals {
 		proposals = append(proposals, rcd.proposal)
@@ -90,37 +187,8 @@ func (r *Repository) Proposals() []*Proposal {
 	return proposals
 }
 
-func (r *Repository) Match(filter *Proposal, max int) []*Proposal {
-	var matches []*Proposal
-
-	for _, p := range r.Proposals() {
-		if filter.ProviderID != "" && filter.ProviderID != p.ProviderID {
-			continue
-		}
-
-		if filter.ServiceType != "" && filter.ServiceType != p.ServiceType {
-			continue
-		}
-
-		if filter.Location.Country != "" && filter.Location.Country != p.Location.Country {
-			continue
-		}
-
-		if filter.Location.IpType != "" && filter.Location.IpType != p.Location.IpType {
-			continue
-		}
-
-		matches = append(matches, p)
-		if len(matches) >= max {
-			break
-		}
-	}
-
-	return matches
-}
-
 func (r *Repository) Providers() []*Provider {
-	r.mu.RLock()
+	r.rlock()
 	defer r.mu.RUnlock()
 	providers := make(map[string]*Provider)
 
@@ -128,12 +196,7 @@ func (r *Repository) Providers() []*Provider {
 		p := rcd.proposal
 
 		if provider, ok := providers[p.ProviderID]; ok {
-			provider.Services = append(provider.Services, Service{
-				ServiceType:    p.ServiceType,
-				Compatibility:  p.Compatibility,
-				Contacts:       p.Contacts,
-				AccessPolicies: p.AccessPolicies,
-			})
+			provider.Services = append(provider.Services, newService(p))
 			continue
 		}
 
@@ -141,14 +204,7 @@ func (r *Repository) Providers() []*Provider {
 			ID:       p.ProviderID,
 			Location: p.Location,
 			Quality:  p.Quality,
-			Services: []Service{
-				{
-					ServiceType:    p.ServiceType,
-					Compatibility:  p.Compatibility,
-					Contacts:       p.Contacts,
-					AccessPolicies: p.AccessPolicies,
-				},
-			},
+			Services: []Service{newService(p)},
 		}
 	}
 
@@ -161,7 +217,7 @@ func (r *Repository) Providers() []*Provider {
 }
 
 func (r *Repository) Countries() []string {
-	r.mu.RLock()
+	r.rlock()
 	defer r.mu.RUnlock()
 	countries := make(map[string]struct{})
 
@@ -178,13 +234,13 @@ func (r *Repository) Countries() []string {
 }
 
 func (r *Repository) CountProposals() int {
-	r.mu.RLock()
+	r.rlock()
 	defer r.mu.RUnlock()
 	return len(r.proposals)
 }
 
 func (r *Repository) CountProviders() int {
-	r.mu.RLock()
+	r.rlock()
 	defer r.mu.RUnlock()
 	providers := make(map[string]bool)
 
@@ -196,25 +252,40 @@ func (r *Repository) CountProviders() int {
 }
 
 func (r *Repository) UpdateQuality(qualityData map[string]*Quality) {
-	r.mu.Lock()
+	r.lock()
 	defer r.mu.Unlock()
 
 	for id, quality := range qualityData {
-		if rcd, ok := r.proposals[id]; ok {
-			rcd.proposal.Quality = quality
-			r.proposals[id] = rcd
+		rcd, ok := r.proposals[id]
+		if !ok {
+			continue
+		}
+
+		changed := (rcd.proposal.Quality == nil) != (quality == nil) ||
+			(quality != nil && *rcd.proposal.Quality != *quality)
+
+		// replace the proposal instead of mutating it, as it may still be referenced by readers
+		updated := *rcd.proposal
+		updated.Quality = quality
+		rcd.proposal = &updated
+		r.proposals[id] = rcd
+
+		if changed {
+			r.changed(EventQualityChanged, rcd.proposal)
 		}
 	}
 }
 
 func (r *Repository) RemoveExpired() int {
-	r.mu.Lock()
+	r.lock()
 	defer r.mu.Unlock()
 	var expired int
 
 	for key, record := range r.proposals {
-		if time.Now().After(record.expires) {
+		if !record.pinned && time.Now().After(record.expires) {
 			delete(r.proposals, key)
+			r.churn.expired(record.proposal)
+			r.changed(EventExpired, record.proposal)
 			expired++
 		}
 	}
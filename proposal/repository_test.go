@@ -0,0 +1,41 @@
+package proposal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGeneration(t *testing.T) {
+	r := newTestRepository()
+	start := r.Generation()
+
+	p := qualityProposal("a", nil)
+	tests := []struct {
+		name   string
+		change func()
+		want   uint64
+	}{
+		{"store", func() { r.Store(p) }, start.ID + 1},
+		{"renew", func() { r.RenewOrStore(p) }, start.ID + 1},
+		{"remove", func() { r.Remove(p.ServiceKey()) }, start.ID + 2},
+		{"remove missing", func() { r.Remove(p.ServiceKey()) }, start.ID + 2},
+	}
+
+	for _, tt := range tests {
+		tt.change()
+		if got := r.Generation().ID; got != tt.want {
+			t.Errorf("%s: generation = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGenerationAcrossRestarts(t *testing.T) {
+	first := newTestRepository(qualityProposal("a", nil))
+	time.Sleep(time.Millisecond)
+	second := newTestRepository(qualityProposal("a", nil))
+
+	// a restarted process with the same contents must not reuse the generation of the previous one
+	if first.Generation().ID >= second.Generation().ID {
+		t.Errorf("generation of the later repository %d is not after %d", second.Generation().ID, first.Generation().ID)
+	}
+}
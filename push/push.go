@@ -0,0 +1,255 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/rs/zerolog/log"
+
+	"github.com/sch8ill/propmon/metrics"
+)
+
+const (
+	ModePushgateway = "pushgateway"
+	ModeRemoteWrite = "remote-write"
+
+	initialBackoff = time.Second
+	maxBackoff     = 5 * time.Minute
+	requestTimeout = 30 * time.Second
+)
+
+type Options struct {
+	URL string
+	// Mode is either pushgateway or remote-write
+	Mode     string
+	Interval time.Duration
+	// Job and Instance are the grouping key of the pushgateway or labels of the remote write series.
+	// The instance defaults to the hostname.
+	Job      string
+	Instance string
+	// BufferSize is the number of remote write requests kept while the endpoint is unreachable
+	BufferSize  int
+	Username    string
+	Password    string
+	BearerToken string
+}
+
+// Pusher periodically pushes the metrics of a gatherer to a pushgateway or a remote write endpoint.
+// Failed pushes are retried with exponential backoff.
+type Pusher struct {
+	gatherer    prometheus.Gatherer
+	options     Options
+	client      *http.Client
+	pushgateway *push.Pusher
+	// queue holds the encoded remote write requests that have not been sent yet
+	queue [][]byte
+	// pending reports whether the pushgateway has not received the latest metrics
+	pending   bool
+	stopCh    chan struct{}
+	waitGroup sync.WaitGroup
+}
+
+// permanentError is returned for requests that are rejected and must not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func NewPusher(gatherer prometheus.Gatherer, options Options) (*Pusher, error) {
+	if options.Instance == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hostname for the instance label: %w", err)
+		}
+		options.Instance = hostname
+	}
+
+	p := &Pusher{
+		gatherer: gatherer,
+		options:  options,
+		client: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: &authTransport{options: options, next: http.DefaultTransport},
+		},
+		stopCh: make(chan struct{}),
+	}
+
+	switch options.Mode {
+	case ModePushgateway:
+		p.pushgateway = push.New(options.URL, options.Job).
+			Gatherer(gatherer).
+			Grouping("instance", options.Instance).
+			Client(p.client)
+	case ModeRemoteWrite:
+	default:
+		return nil, fmt.Errorf("unknown push mode %q", options.Mode)
+	}
+
+	return p, nil
+}
+
+func (p *Pusher) Start() {
+	log.Debug().Msg("Starting metrics pusher")
+	p.waitGroup.Add(1)
+	go p.run()
+}
+
+// Stop stops the pusher after trying to push the buffered metrics a last time.
+func (p *Pusher) Stop() {
+	close(p.stopCh)
+	p.waitGroup.Wait()
+
+	if err := p.flush(); err != nil {
+		log.Warn().Err(err).Msg("Failed to push metrics on shutdown")
+	}
+}
+
+func (p *Pusher) run() {
+	defer p.waitGroup.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var next time.Time
+	var backoff time.Duration
+	for {
+		select {
+		case <-p.stopCh:
+			return
+
+		case <-timer.C:
+		}
+
+		if !time.Now().Before(next) {
+			if err := p.collect(); err != nil {
+				log.Warn().Err(err).Msg("Failed to gather metrics")
+			}
+			next = time.Now().Add(p.options.Interval)
+		}
+
+		if err := p.flush(); err != nil {
+			metrics.PushFailed()
+			backoff = min(max(2*backoff, initialBackoff), maxBackoff)
+			log.Warn().Err(err).Dur("retry", backoff).Msg("Failed to push metrics")
+			timer.Reset(min(backoff, time.Until(next)))
+			continue
+		}
+
+		backoff = 0
+		timer.Reset(time.Until(next))
+	}
+}
+
+// collect gathers the metrics that are pushed next.
+func (p *Pusher) collect() error {
+	if p.pushgateway != nil {
+		// the pushgateway only keeps the latest metrics, which are gathered when pushing
+		p.pending = true
+		return nil
+	}
+
+	families, err := p.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+
+	extra := []label{{"job", p.options.Job}, {"instance", p.options.Instance}}
+	request := encodeWriteRequest(families, extra, time.Now().UnixMilli())
+	p.queue = append(p.queue, s2.EncodeSnappy(nil, request))
+
+	if dropped := len(p.queue) - p.options.BufferSize; dropped > 0 {
+		p.queue = p.queue[dropped:]
+		metrics.PushDropped(dropped)
+		log.Warn().Int("dropped", dropped).Msg("Push buffer is full, dropping the oldest metrics")
+	}
+	return nil
+}
+
+// flush pushes the pending metrics in order and stops at the first failure.
+func (p *Pusher) flush() error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	if p.pushgateway != nil {
+		if !p.pending {
+			return nil
+		}
+		if err := p.pushgateway.PushContext(ctx); err != nil {
+			return err
+		}
+		p.pending = false
+		return nil
+	}
+
+	for len(p.queue) > 0 {
+		err := p.write(ctx, p.queue[0])
+		var permanent permanentError
+		if errors.As(err, &permanent) {
+			metrics.PushDropped(1)
+			log.Warn().Err(err).Msg("Remote write request rejected, dropping it")
+		} else if err != nil {
+			return err
+		}
+		p.queue = p.queue[1:]
+	}
+	return nil
+}
+
+func (p *Pusher) write(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.options.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create remote write request: %w", err)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "propmon")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send remote write request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode/100 == 2 {
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	err = fmt.Errorf("remote write endpoint returned %s: %s", res.Status, bytes.TrimSpace(msg))
+	// client errors other than rate limiting will not succeed on a retry
+	if res.StatusCode/100 == 4 && res.StatusCode != http.StatusTooManyRequests {
+		return permanentError{err}
+	}
+	return err
+}
+
+// authTransport adds basic or bearer authentication to the requests.
+type authTransport struct {
+	options Options
+	next    http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	switch {
+	case t.options.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+t.options.BearerToken)
+	case t.options.Username != "":
+		req.SetBasicAuth(t.options.Username, t.options.Password)
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,198 @@
+package push
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func init() {
+	log.Logger = zerolog.Nop()
+}
+
+// testEndpoint is a remote write endpoint that answers with the queued status codes and 204 afterwards.
+type testEndpoint struct {
+	statuses []int
+	bodies   [][]byte
+	headers  []http.Header
+	mu       sync.Mutex
+}
+
+func (e *testEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	e.bodies = append(e.bodies, body)
+	e.headers = append(e.headers, r.Header)
+
+	status := http.StatusNoContent
+	if len(e.statuses) > 0 {
+		status, e.statuses = e.statuses[0], e.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func newTestPusher(t *testing.T, endpoint *testEndpoint, options Options) (*Pusher, prometheus.Gauge) {
+	t.Helper()
+
+	server := httptest.NewServer(endpoint)
+	t.Cleanup(server.Close)
+
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_value", Help: "value"})
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(gauge)
+
+	options.URL = server.URL
+	options.Mode = ModeRemoteWrite
+	options.Interval = time.Hour
+	options.Job = "propmon"
+	options.Instance = "host"
+	if options.BufferSize == 0 {
+		options.BufferSize = 10
+	}
+
+	p, err := NewPusher(registry, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, gauge
+}
+
+// queuedValues decodes the value of test_value in every buffered request.
+func queuedValues(t *testing.T, queue [][]byte) []float64 {
+	t.Helper()
+
+	values := make([]float64, 0, len(queue))
+	for _, body := range queue {
+		values = append(values, decodeBody(t, body)[0].value)
+	}
+	return values
+}
+
+func decodeBody(t *testing.T, body []byte) []testSample {
+	t.Helper()
+
+	request, err := s2.Decode(nil, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decodeWriteRequest(t, request)
+}
+
+func TestPusherBufferDropsOldest(t *testing.T) {
+	p, gauge := newTestPusher(t, &testEndpoint{}, Options{BufferSize: 2})
+
+	for i := range 4 {
+		gauge.Set(float64(i))
+		if err := p.collect(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := queuedValues(t, p.queue); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("queued values = %v, want [2 3]", got)
+	}
+}
+
+func TestPusherFlush(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		// attempts is the number of flushes until the queue is empty
+		attempts int
+		// requests is the number of requests the endpoint receives
+		requests int
+	}{
+		{"success", nil, 1, 2},
+		{"server error is retried", []int{http.StatusInternalServerError}, 2, 3},
+		{"rate limit is retried", []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}, 3, 4},
+		{"client error is dropped", []int{http.StatusBadRequest}, 1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := &testEndpoint{statuses: tt.statuses}
+			p, gauge := newTestPusher(t, endpoint, Options{})
+
+			for i := range 2 {
+				gauge.Set(float64(i))
+				if err := p.collect(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for attempt := 1; attempt <= tt.attempts; attempt++ {
+				err := p.flush()
+				if attempt < tt.attempts {
+					if err == nil {
+						t.Fatalf("flush %d succeeded, want an error", attempt)
+					}
+					// failed requests stay at the front of the queue
+					if got := queuedValues(t, p.queue); len(got) != 2 || got[0] != 0 {
+						t.Fatalf("queued values after failure = %v, want [0 1]", got)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("flush %d failed: %v", attempt, err)
+				}
+			}
+
+			if len(p.queue) != 0 {
+				t.Errorf("%d requests left in the queue", len(p.queue))
+			}
+			if len(endpoint.bodies) != tt.requests {
+				t.Fatalf("endpoint received %d requests, want %d", len(endpoint.bodies), tt.requests)
+			}
+			// the requests are sent in order and retries resend the same request
+			last := decodeBody(t, endpoint.bodies[len(endpoint.bodies)-1])[0].value
+			first := decodeBody(t, endpoint.bodies[0])[0].value
+			if first != 0 || last != 1 {
+				t.Errorf("first and last values = %v and %v, want 0 and 1", first, last)
+			}
+		})
+	}
+}
+
+func TestPusherHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		auth    string
+	}{
+		{"no authentication", Options{}, ""},
+		{"basic", Options{Username: "user", Password: "pass"}, "Basic dXNlcjpwYXNz"},
+		{"bearer", Options{BearerToken: "token"}, "Bearer token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := &testEndpoint{}
+			p, _ := newTestPusher(t, endpoint, tt.options)
+			if err := p.collect(); err != nil {
+				t.Fatal(err)
+			}
+			if err := p.flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			header := endpoint.headers[0]
+			if got := header.Get("Authorization"); got != tt.auth {
+				t.Errorf("Authorization = %q, want %q", got, tt.auth)
+			}
+			if header.Get("Content-Encoding") != "snappy" || header.Get("Content-Type") != "application/x-protobuf" ||
+				header.Get("X-Prometheus-Remote-Write-Version") != "0.1.0" {
+				t.Errorf("remote write headers = %v", header)
+			}
+		})
+	}
+}
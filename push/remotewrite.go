@@ -0,0 +1,95 @@
+package push
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type label struct {
+	name  string
+	value string
+}
+
+// encodeWriteRequest encodes the metric families as a remote write 1.0 WriteRequest protobuf message.
+// Histograms and summaries are split into their bucket, quantile, sum and count series.
+func encodeWriteRequest(families []*dto.MetricFamily, extra []label, timestamp int64) []byte {
+	var b []byte
+	for _, family := range families {
+		name := family.GetName()
+		for _, m := range family.GetMetric() {
+			labels := slices.Clone(extra)
+			for _, l := range m.GetLabel() {
+				labels = append(labels, label{l.GetName(), l.GetValue()})
+			}
+
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				b = appendSeries(b, name, labels, m.GetCounter().GetValue(), timestamp)
+			case dto.MetricType_GAUGE:
+				b = appendSeries(b, name, labels, m.GetGauge().GetValue(), timestamp)
+			case dto.MetricType_UNTYPED:
+				b = appendSeries(b, name, labels, m.GetUntyped().GetValue(), timestamp)
+
+			case dto.MetricType_HISTOGRAM:
+				h := m.GetHistogram()
+				for _, bucket := range h.GetBucket() {
+					le := append(slices.Clone(labels), label{"le", formatFloat(bucket.GetUpperBound())})
+					b = appendSeries(b, name+"_bucket", le, float64(bucket.GetCumulativeCount()), timestamp)
+				}
+				le := append(slices.Clone(labels), label{"le", "+Inf"})
+				b = appendSeries(b, name+"_bucket", le, float64(h.GetSampleCount()), timestamp)
+				b = appendSeries(b, name+"_sum", labels, h.GetSampleSum(), timestamp)
+				b = appendSeries(b, name+"_count", labels, float64(h.GetSampleCount()), timestamp)
+
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				for _, q := range s.GetQuantile() {
+					quantile := append(slices.Clone(labels), label{"quantile", formatFloat(q.GetQuantile())})
+					b = appendSeries(b, name, quantile, q.GetValue(), timestamp)
+				}
+				b = appendSeries(b, name+"_sum", labels, s.GetSampleSum(), timestamp)
+				b = appendSeries(b, name+"_count", labels, float64(s.GetSampleCount()), timestamp)
+			}
+		}
+	}
+	return b
+}
+
+// appendSeries appends a TimeSeries with a single sample to the WriteRequest.
+func appendSeries(b []byte, name string, labels []label, value float64, timestamp int64) []byte {
+	labels = append([]label{{"__name__", name}}, labels...)
+	slices.SortFunc(labels, func(a, b label) int { return strings.Compare(a.name, b.name) })
+
+	var series []byte
+	for _, l := range labels {
+		var encoded []byte
+		encoded = protowire.AppendTag(encoded, 1, protowire.BytesType)
+		encoded = protowire.AppendString(encoded, l.name)
+		encoded = protowire.AppendTag(encoded, 2, protowire.BytesType)
+		encoded = protowire.AppendString(encoded, l.value)
+
+		series = protowire.AppendTag(series, 1, protowire.BytesType)
+		series = protowire.AppendBytes(series, encoded)
+	}
+
+	var sample []byte
+	sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+	sample = protowire.AppendFixed64(sample, math.Float64bits(value))
+	sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+	sample = protowire.AppendVarint(sample, uint64(timestamp))
+
+	series = protowire.AppendTag(series, 2, protowire.BytesType)
+	series = protowire.AppendBytes(series, sample)
+
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	return protowire.AppendBytes(b, series)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
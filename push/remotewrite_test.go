@@ -0,0 +1,174 @@
+package push
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type testSample struct {
+	labels    []label
+	value     float64
+	timestamp int64
+}
+
+// decodeWriteRequest decodes the time series of a WriteRequest with a single sample each.
+func decodeWriteRequest(t *testing.T, b []byte) []testSample {
+	t.Helper()
+
+	var samples []testSample
+	for _, series := range decodeMessages(t, b, 1) {
+		var s testSample
+		for _, l := range decodeMessages(t, series, 1) {
+			fields := decodeFields(t, l)
+			s.labels = append(s.labels, label{string(fields[1].bytes), string(fields[2].bytes)})
+		}
+
+		encoded := decodeMessages(t, series, 2)
+		if len(encoded) != 1 {
+			t.Fatalf("series has %d samples, want 1", len(encoded))
+		}
+		fields := decodeFields(t, encoded[0])
+		s.value = math.Float64frombits(fields[1].number)
+		s.timestamp = int64(fields[2].number)
+
+		samples = append(samples, s)
+	}
+	return samples
+}
+
+type testField struct {
+	bytes  []byte
+	number uint64
+}
+
+// decodeFields decodes the last value of each field of a message.
+func decodeFields(t *testing.T, b []byte) map[protowire.Number]testField {
+	t.Helper()
+
+	fields := make(map[protowire.Number]testField)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatal(protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		var f testField
+		switch typ {
+		case protowire.BytesType:
+			f.bytes, n = protowire.ConsumeBytes(b)
+		case protowire.Fixed64Type:
+			f.number, n = protowire.ConsumeFixed64(b)
+		case protowire.VarintType:
+			f.number, n = protowire.ConsumeVarint(b)
+		default:
+			t.Fatalf("unexpected wire type %d", typ)
+		}
+		if n < 0 {
+			t.Fatal(protowire.ParseError(n))
+		}
+		b = b[n:]
+		fields[num] = f
+	}
+	return fields
+}
+
+// decodeMessages returns the values of the repeated message field num.
+func decodeMessages(t *testing.T, b []byte, num protowire.Number) [][]byte {
+	t.Helper()
+
+	var messages [][]byte
+	for len(b) > 0 {
+		n, typ, tagLen := protowire.ConsumeTag(b)
+		if tagLen < 0 {
+			t.Fatal(protowire.ParseError(tagLen))
+		}
+		valueLen := protowire.ConsumeFieldValue(n, typ, b[tagLen:])
+		if valueLen < 0 {
+			t.Fatal(protowire.ParseError(valueLen))
+		}
+		if n == num && typ == protowire.BytesType {
+			value, _ := protowire.ConsumeBytes(b[tagLen:])
+			messages = append(messages, value)
+		}
+		b = b[tagLen+valueLen:]
+	}
+	return messages
+}
+
+func labelValue(labels []label, name string) string {
+	for _, l := range labels {
+		if l.name == name {
+			return l.value
+		}
+	}
+	return ""
+}
+
+func TestEncodeWriteRequest(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_requests", Help: "requests"}, []string{"zone", "code"})
+	counter.WithLabelValues("eu", "200").Add(3)
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_temperature", Help: "temperature"})
+	gauge.Set(-1.5)
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_latency", Help: "latency", Buckets: []float64{0.5, 1}})
+	histogram.Observe(0.25)
+	histogram.Observe(2)
+	registry.MustRegister(counter, gauge, histogram)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const timestamp = 1700000000123
+	samples := decodeWriteRequest(t, encodeWriteRequest(families, []label{{"job", "propmon"}, {"instance", "host"}}, timestamp))
+
+	tests := []struct {
+		name  string
+		le    string
+		value float64
+	}{
+		{"test_latency_bucket", "0.5", 1},
+		{"test_latency_bucket", "1", 1},
+		{"test_latency_bucket", "+Inf", 2},
+		{"test_latency_sum", "", 2.25},
+		{"test_latency_count", "", 2},
+		{"test_requests", "", 3},
+		{"test_temperature", "", -1.5},
+	}
+
+	if len(samples) != len(tests) {
+		t.Fatalf("got %d series, want %d", len(samples), len(tests))
+	}
+
+	for i, tt := range tests {
+		s := samples[i]
+		if got := labelValue(s.labels, "__name__"); got != tt.name || labelValue(s.labels, "le") != tt.le {
+			t.Errorf("series %d = %s{le=%q}, want %s{le=%q}", i, got, labelValue(s.labels, "le"), tt.name, tt.le)
+		}
+		if s.value != tt.value {
+			t.Errorf("%s{le=%q} = %v, want %v", tt.name, tt.le, s.value, tt.value)
+		}
+		if s.timestamp != timestamp {
+			t.Errorf("%s timestamp = %d, want %d", tt.name, s.timestamp, timestamp)
+		}
+		// remote write requires the labels of a series to be sorted by name
+		if !slices.IsSortedFunc(s.labels, func(a, b label) int { return strings.Compare(a.name, b.name) }) {
+			t.Errorf("%s labels are not sorted: %v", tt.name, s.labels)
+		}
+		if labelValue(s.labels, "job") != "propmon" || labelValue(s.labels, "instance") != "host" {
+			t.Errorf("%s is missing the extra labels: %v", tt.name, s.labels)
+		}
+	}
+
+	if got := samples[5].labels; !slices.Equal(got, []label{{"__name__", "test_requests"}, {"code", "200"}, {"instance", "host"}, {"job", "propmon"}, {"zone", "eu"}}) {
+		t.Errorf("counter labels = %v", got)
+	}
+}
@@ -17,6 +17,7 @@ type Service struct {
 	interval         time.Duration
 	stopCh           chan struct{}
 	waitGroup        sync.WaitGroup
+	updateMu         sync.Mutex
 }
 
 func NewQualityService(oracle *Oracle, repository *proposal.Repository, interval time.Duration, proposalLifetime time.Duration) *Service {
@@ -25,6 +26,7 @@ func NewQualityService(oracle *Oracle, repository *proposal.Repository, interval
 		repository:       repository,
 		proposalLifetime: proposalLifetime,
 		interval:         interval,
+		stopCh:           make(chan struct{}),
 	}
 }
 
@@ -39,32 +41,42 @@ func (s *Service) Stop() {
 	s.waitGroup.Wait()
 }
 
+// Refresh immediately fetches and applies the quality data and returns the number of entries.
+func (s *Service) Refresh() (int, error) {
+	return s.update()
+}
+
 func (s *Service) run() {
-	// wait until most proposals have been captured to not waste any quality entries
-	time.Sleep(s.proposalLifetime)
 	defer s.waitGroup.Done()
 
+	// wait until most proposals have been captured to not waste any quality entries
+	timer := time.NewTimer(s.proposalLifetime)
+	defer timer.Stop()
+
 	for {
 		select {
 		case <-s.stopCh:
 			return
 
-		default:
-			if err := s.update(); err != nil {
+		case <-timer.C:
+			if _, err := s.update(); err != nil {
 				log.Warn().Err(err).Msg("Failed to update quality data")
 			}
-			time.Sleep(s.interval)
+			timer.Reset(s.interval)
 		}
 	}
 }
 
-func (s *Service) update() error {
+func (s *Service) update() (int, error) {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
 	qualityData, err := s.oracle.Quality()
 	if err != nil {
-		return fmt.Errorf("failed to fetch quality data: %w", err)
+		return 0, fmt.Errorf("failed to fetch quality data: %w", err)
 	}
 	log.Debug().Msgf("Fetched %d quality entries", len(qualityData))
 	s.repository.UpdateQuality(qualityData)
 
-	return nil
+	return len(qualityData), nil
 }
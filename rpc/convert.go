@@ -0,0 +1,109 @@
+package rpc
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/sch8ill/propmon/proposal"
+	"github.com/sch8ill/propmon/rpc/propmonpb"
+)
+
+var eventTypes = map[proposal.EventType]propmonpb.Event_Type{
+	proposal.EventRegistered:      propmonpb.Event_TYPE_REGISTERED,
+	proposal.EventPingAfterExpiry: propmonpb.Event_TYPE_PING_AFTER_EXPIRY,
+	proposal.EventUnregistered:    propmonpb.Event_TYPE_UNREGISTERED,
+	proposal.EventExpired:         propmonpb.Event_TYPE_EXPIRED,
+	proposal.EventEvicted:         propmonpb.Event_TYPE_EVICTED,
+	proposal.EventQualityChanged:  propmonpb.Event_TYPE_QUALITY_CHANGED,
+	proposal.EventReset:           propmonpb.Event_TYPE_RESET,
+}
+
+func toProposal(p *proposal.Proposal) *propmonpb.Proposal {
+	if p == nil {
+		return nil
+	}
+
+	return &propmonpb.Proposal{
+		Format:         p.Format,
+		Compatibility:  int32(p.Compatibility),
+		ProviderId:     p.ProviderID,
+		ServiceType:    p.ServiceType,
+		Location:       toLocation(p.Location),
+		Contacts:       toContacts(p.Contacts),
+		Quality:        toQuality(p.Quality),
+		AccessPolicies: toAccessPolicies(p.AccessPolicies),
+	}
+}
+
+func toProvider(p *proposal.Provider) *propmonpb.Provider {
+	provider := &propmonpb.Provider{
+		Id:       p.ID,
+		Location: toLocation(p.Location),
+		Quality:  toQuality(p.Quality),
+		Services: make([]*propmonpb.Service, 0, len(p.Services)),
+	}
+
+	for _, s := range p.Services {
+		provider.Services = append(provider.Services, &propmonpb.Service{
+			ServiceType:    s.ServiceType,
+			Compatibility:  int32(s.Compatibility),
+			Contacts:       toContacts(s.Contacts),
+			AccessPolicies: toAccessPolicies(s.AccessPolicies),
+		})
+	}
+
+	return provider
+}
+
+func toLocation(l proposal.Location) *propmonpb.Location {
+	return &propmonpb.Location{
+		Continent: l.Continent,
+		Country:   l.Country,
+		Region:    l.Region,
+		City:      l.City,
+		Asn:       int64(l.Asn),
+		Isp:       l.Isp,
+		IpType:    l.IpType,
+	}
+}
+
+func toContacts(contacts []proposal.Contact) []*propmonpb.Contact {
+	converted := make([]*propmonpb.Contact, 0, len(contacts))
+	for _, c := range contacts {
+		converted = append(converted, &propmonpb.Contact{
+			Type:       c.Type,
+			Definition: &propmonpb.ContactDefinition{BrokerAddresses: c.Definition.BrokerAddresses},
+		})
+	}
+	return converted
+}
+
+func toQuality(q *proposal.Quality) *propmonpb.Quality {
+	if q == nil {
+		return nil
+	}
+
+	return &propmonpb.Quality{
+		Quality:        q.Quality,
+		Latency:        q.Latency,
+		Bandwidth:      q.Bandwidth,
+		Uptime:         q.Uptime,
+		RestrictedNode: q.RestrictedNode,
+	}
+}
+
+func toAccessPolicies(policies []proposal.AccessPolicy) []*propmonpb.AccessPolicy {
+	converted := make([]*propmonpb.AccessPolicy, 0, len(policies))
+	for _, p := range policies {
+		converted = append(converted, &propmonpb.AccessPolicy{Id: p.ID, Source: p.Source})
+	}
+	return converted
+}
+
+func toEvent(e proposal.Event) *propmonpb.Event {
+	return &propmonpb.Event{
+		Id:       e.ID,
+		Type:     eventTypes[e.Type],
+		Time:     timestamppb.New(e.Time),
+		Proposal: toProposal(e.Proposal),
+	}
+}
@@ -0,0 +1,1292 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: propmon.proto
+
+package propmonpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Event_Type int32
+
+const (
+	Event_TYPE_UNSPECIFIED       Event_Type = 0
+	Event_TYPE_REGISTERED        Event_Type = 1
+	Event_TYPE_PING_AFTER_EXPIRY Event_Type = 2
+	Event_TYPE_UNREGISTERED      Event_Type = 3
+	Event_TYPE_EXPIRED           Event_Type = 4
+	Event_TYPE_EVICTED           Event_Type = 5
+	Event_TYPE_QUALITY_CHANGED   Event_Type = 6
+	// TYPE_RESET replaces the replay if the events after last_event_id are no longer buffered,
+	// clients have to reload the full state. It carries no proposal.
+	Event_TYPE_RESET Event_Type = 7
+)
+
+// Enum value maps for Event_Type.
+var (
+	Event_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_REGISTERED",
+		2: "TYPE_PING_AFTER_EXPIRY",
+		3: "TYPE_UNREGISTERED",
+		4: "TYPE_EXPIRED",
+		5: "TYPE_EVICTED",
+		6: "TYPE_QUALITY_CHANGED",
+		7: "TYPE_RESET",
+	}
+	Event_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED":       0,
+		"TYPE_REGISTERED":        1,
+		"TYPE_PING_AFTER_EXPIRY": 2,
+		"TYPE_UNREGISTERED":      3,
+		"TYPE_EXPIRED":           4,
+		"TYPE_EVICTED":           5,
+		"TYPE_QUALITY_CHANGED":   6,
+		"TYPE_RESET":             7,
+	}
+)
+
+func (x Event_Type) Enum() *Event_Type {
+	p := new(Event_Type)
+	*p = x
+	return p
+}
+
+func (x Event_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Event_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_propmon_proto_enumTypes[0].Descriptor()
+}
+
+func (Event_Type) Type() protoreflect.EnumType {
+	return &file_propmon_proto_enumTypes[0]
+}
+
+func (x Event_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Event_Type.Descriptor instead.
+func (Event_Type) EnumDescriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{15, 0}
+}
+
+type ListProposalsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// filter uses the query parameters of the rest api, e.g. {"country": "DE,FR", "quality_min": "2"}
+	Filter map[string]string `protobuf:"bytes,1,rep,name=filter,proto3" json:"filter,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// sort is a sort field, prefixed with "-" for descending order
+	Sort          string `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	PageSize      int32  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProposalsRequest) Reset() {
+	*x = ListProposalsRequest{}
+	mi := &file_propmon_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProposalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProposalsRequest) ProtoMessage() {}
+
+func (x *ListProposalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProposalsRequest.ProtoReflect.Descriptor instead.
+func (*ListProposalsRequest) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListProposalsRequest) GetFilter() map[string]string {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListProposalsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListProposalsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListProposalsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListProposalsResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Proposals []*Proposal            `protobuf:"bytes,1,rep,name=proposals,proto3" json:"proposals,omitempty"`
+	// next_page_token is empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProposalsResponse) Reset() {
+	*x = ListProposalsResponse{}
+	mi := &file_propmon_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProposalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProposalsResponse) ProtoMessage() {}
+
+func (x *ListProposalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProposalsResponse.ProtoReflect.Descriptor instead.
+func (*ListProposalsResponse) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListProposalsResponse) GetProposals() []*Proposal {
+	if x != nil {
+		return x.Proposals
+	}
+	return nil
+}
+
+func (x *ListProposalsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetProposalRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key is <provider id>.<service type>
+	Key           string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProposalRequest) Reset() {
+	*x = GetProposalRequest{}
+	mi := &file_propmon_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProposalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProposalRequest) ProtoMessage() {}
+
+func (x *GetProposalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProposalRequest.ProtoReflect.Descriptor instead.
+func (*GetProposalRequest) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetProposalRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type ListProvidersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// filter selects providers with at least one matching proposal
+	Filter        map[string]string `protobuf:"bytes,1,rep,name=filter,proto3" json:"filter,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	PageSize      int32             `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string            `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProvidersRequest) Reset() {
+	*x = ListProvidersRequest{}
+	mi := &file_propmon_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProvidersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProvidersRequest) ProtoMessage() {}
+
+func (x *ListProvidersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProvidersRequest.ProtoReflect.Descriptor instead.
+func (*ListProvidersRequest) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListProvidersRequest) GetFilter() map[string]string {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListProvidersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListProvidersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListProvidersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Providers     []*Provider            `protobuf:"bytes,1,rep,name=providers,proto3" json:"providers,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProvidersResponse) Reset() {
+	*x = ListProvidersResponse{}
+	mi := &file_propmon_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProvidersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProvidersResponse) ProtoMessage() {}
+
+func (x *ListProvidersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProvidersResponse.ProtoReflect.Descriptor instead.
+func (*ListProvidersResponse) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListProvidersResponse) GetProviders() []*Provider {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+func (x *ListProvidersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetProviderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProviderRequest) Reset() {
+	*x = GetProviderRequest{}
+	mi := &file_propmon_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProviderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProviderRequest) ProtoMessage() {}
+
+func (x *GetProviderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProviderRequest.ProtoReflect.Descriptor instead.
+func (*GetProviderRequest) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetProviderRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type WatchRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter map[string]string      `protobuf:"bytes,1,rep,name=filter,proto3" json:"filter,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// last_event_id replays the buffered events published after it, or sends a TYPE_RESET event
+	// if some of them are no longer buffered
+	LastEventId   uint64 `protobuf:"varint,2,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_propmon_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WatchRequest) GetFilter() map[string]string {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetLastEventId() uint64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+type Proposal struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	Compatibility int32                  `protobuf:"varint,2,opt,name=compatibility,proto3" json:"compatibility,omitempty"`
+	ProviderId    string                 `protobuf:"bytes,3,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	ServiceType   string                 `protobuf:"bytes,4,opt,name=service_type,json=serviceType,proto3" json:"service_type,omitempty"`
+	Location      *Location              `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
+	Contacts      []*Contact             `protobuf:"bytes,6,rep,name=contacts,proto3" json:"contacts,omitempty"`
+	// quality is unset if the quality oracle has no data for the proposal
+	Quality        *Quality        `protobuf:"bytes,7,opt,name=quality,proto3" json:"quality,omitempty"`
+	AccessPolicies []*AccessPolicy `protobuf:"bytes,8,rep,name=access_policies,json=accessPolicies,proto3" json:"access_policies,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Proposal) Reset() {
+	*x = Proposal{}
+	mi := &file_propmon_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Proposal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Proposal) ProtoMessage() {}
+
+func (x *Proposal) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Proposal.ProtoReflect.Descriptor instead.
+func (*Proposal) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Proposal) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *Proposal) GetCompatibility() int32 {
+	if x != nil {
+		return x.Compatibility
+	}
+	return 0
+}
+
+func (x *Proposal) GetProviderId() string {
+	if x != nil {
+		return x.ProviderId
+	}
+	return ""
+}
+
+func (x *Proposal) GetServiceType() string {
+	if x != nil {
+		return x.ServiceType
+	}
+	return ""
+}
+
+func (x *Proposal) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Proposal) GetContacts() []*Contact {
+	if x != nil {
+		return x.Contacts
+	}
+	return nil
+}
+
+func (x *Proposal) GetQuality() *Quality {
+	if x != nil {
+		return x.Quality
+	}
+	return nil
+}
+
+func (x *Proposal) GetAccessPolicies() []*AccessPolicy {
+	if x != nil {
+		return x.AccessPolicies
+	}
+	return nil
+}
+
+type Provider struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Location      *Location              `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Quality       *Quality               `protobuf:"bytes,3,opt,name=quality,proto3" json:"quality,omitempty"`
+	Services      []*Service             `protobuf:"bytes,4,rep,name=services,proto3" json:"services,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Provider) Reset() {
+	*x = Provider{}
+	mi := &file_propmon_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Provider) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Provider) ProtoMessage() {}
+
+func (x *Provider) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Provider.ProtoReflect.Descriptor instead.
+func (*Provider) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Provider) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Provider) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Provider) GetQuality() *Quality {
+	if x != nil {
+		return x.Quality
+	}
+	return nil
+}
+
+func (x *Provider) GetServices() []*Service {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+type Service struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServiceType    string                 `protobuf:"bytes,1,opt,name=service_type,json=serviceType,proto3" json:"service_type,omitempty"`
+	Compatibility  int32                  `protobuf:"varint,2,opt,name=compatibility,proto3" json:"compatibility,omitempty"`
+	Contacts       []*Contact             `protobuf:"bytes,3,rep,name=contacts,proto3" json:"contacts,omitempty"`
+	AccessPolicies []*AccessPolicy        `protobuf:"bytes,4,rep,name=access_policies,json=accessPolicies,proto3" json:"access_policies,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Service) Reset() {
+	*x = Service{}
+	mi := &file_propmon_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Service) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Service) ProtoMessage() {}
+
+func (x *Service) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Service.ProtoReflect.Descriptor instead.
+func (*Service) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Service) GetServiceType() string {
+	if x != nil {
+		return x.ServiceType
+	}
+	return ""
+}
+
+func (x *Service) GetCompatibility() int32 {
+	if x != nil {
+		return x.Compatibility
+	}
+	return 0
+}
+
+func (x *Service) GetContacts() []*Contact {
+	if x != nil {
+		return x.Contacts
+	}
+	return nil
+}
+
+func (x *Service) GetAccessPolicies() []*AccessPolicy {
+	if x != nil {
+		return x.AccessPolicies
+	}
+	return nil
+}
+
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Continent     string                 `protobuf:"bytes,1,opt,name=continent,proto3" json:"continent,omitempty"`
+	Country       string                 `protobuf:"bytes,2,opt,name=country,proto3" json:"country,omitempty"`
+	Region        string                 `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	City          string                 `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
+	Asn           int64                  `protobuf:"varint,5,opt,name=asn,proto3" json:"asn,omitempty"`
+	Isp           string                 `protobuf:"bytes,6,opt,name=isp,proto3" json:"isp,omitempty"`
+	IpType        string                 `protobuf:"bytes,7,opt,name=ip_type,json=ipType,proto3" json:"ip_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_propmon_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Location) GetContinent() string {
+	if x != nil {
+		return x.Continent
+	}
+	return ""
+}
+
+func (x *Location) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Location) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Location) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Location) GetAsn() int64 {
+	if x != nil {
+		return x.Asn
+	}
+	return 0
+}
+
+func (x *Location) GetIsp() string {
+	if x != nil {
+		return x.Isp
+	}
+	return ""
+}
+
+func (x *Location) GetIpType() string {
+	if x != nil {
+		return x.IpType
+	}
+	return ""
+}
+
+type Contact struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Definition    *ContactDefinition     `protobuf:"bytes,2,opt,name=definition,proto3" json:"definition,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Contact) Reset() {
+	*x = Contact{}
+	mi := &file_propmon_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Contact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contact) ProtoMessage() {}
+
+func (x *Contact) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contact.ProtoReflect.Descriptor instead.
+func (*Contact) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Contact) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Contact) GetDefinition() *ContactDefinition {
+	if x != nil {
+		return x.Definition
+	}
+	return nil
+}
+
+type ContactDefinition struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	BrokerAddresses []string               `protobuf:"bytes,1,rep,name=broker_addresses,json=brokerAddresses,proto3" json:"broker_addresses,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ContactDefinition) Reset() {
+	*x = ContactDefinition{}
+	mi := &file_propmon_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContactDefinition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContactDefinition) ProtoMessage() {}
+
+func (x *ContactDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContactDefinition.ProtoReflect.Descriptor instead.
+func (*ContactDefinition) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ContactDefinition) GetBrokerAddresses() []string {
+	if x != nil {
+		return x.BrokerAddresses
+	}
+	return nil
+}
+
+type AccessPolicy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessPolicy) Reset() {
+	*x = AccessPolicy{}
+	mi := &file_propmon_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessPolicy) ProtoMessage() {}
+
+func (x *AccessPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessPolicy.ProtoReflect.Descriptor instead.
+func (*AccessPolicy) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AccessPolicy) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AccessPolicy) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type Quality struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Quality        float64                `protobuf:"fixed64,1,opt,name=quality,proto3" json:"quality,omitempty"`
+	Latency        float64                `protobuf:"fixed64,2,opt,name=latency,proto3" json:"latency,omitempty"`
+	Bandwidth      float64                `protobuf:"fixed64,3,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`
+	Uptime         float64                `protobuf:"fixed64,4,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	RestrictedNode bool                   `protobuf:"varint,5,opt,name=restricted_node,json=restrictedNode,proto3" json:"restricted_node,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Quality) Reset() {
+	*x = Quality{}
+	mi := &file_propmon_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quality) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quality) ProtoMessage() {}
+
+func (x *Quality) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quality.ProtoReflect.Descriptor instead.
+func (*Quality) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Quality) GetQuality() float64 {
+	if x != nil {
+		return x.Quality
+	}
+	return 0
+}
+
+func (x *Quality) GetLatency() float64 {
+	if x != nil {
+		return x.Latency
+	}
+	return 0
+}
+
+func (x *Quality) GetBandwidth() float64 {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return 0
+}
+
+func (x *Quality) GetUptime() float64 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+func (x *Quality) GetRestrictedNode() bool {
+	if x != nil {
+		return x.RestrictedNode
+	}
+	return false
+}
+
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          Event_Type             `protobuf:"varint,2,opt,name=type,proto3,enum=propmon.v1.Event_Type" json:"type,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	Proposal      *Proposal              `protobuf:"bytes,4,opt,name=proposal,proto3" json:"proposal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_propmon_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_propmon_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_propmon_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Event) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Event) GetType() Event_Type {
+	if x != nil {
+		return x.Type
+	}
+	return Event_TYPE_UNSPECIFIED
+}
+
+func (x *Event) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Event) GetProposal() *Proposal {
+	if x != nil {
+		return x.Proposal
+	}
+	return nil
+}
+
+var File_propmon_proto protoreflect.FileDescriptor
+
+const file_propmon_proto_rawDesc = "" +
+	"\n" +
+	"\rpropmon.proto\x12\n" +
+	"propmon.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe7\x01\n" +
+	"\x14ListProposalsRequest\x12D\n" +
+	"\x06filter\x18\x01 \x03(\v2,.propmon.v1.ListProposalsRequest.FilterEntryR\x06filter\x12\x12\n" +
+	"\x04sort\x18\x02 \x01(\tR\x04sort\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x1a9\n" +
+	"\vFilterEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"s\n" +
+	"\x15ListProposalsResponse\x122\n" +
+	"\tproposals\x18\x01 \x03(\v2\x14.propmon.v1.ProposalR\tproposals\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"&\n" +
+	"\x12GetProposalRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"\xd3\x01\n" +
+	"\x14ListProvidersRequest\x12D\n" +
+	"\x06filter\x18\x01 \x03(\v2,.propmon.v1.ListProvidersRequest.FilterEntryR\x06filter\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x1a9\n" +
+	"\vFilterEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"s\n" +
+	"\x15ListProvidersResponse\x122\n" +
+	"\tproviders\x18\x01 \x03(\v2\x14.propmon.v1.ProviderR\tproviders\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"$\n" +
+	"\x12GetProviderRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xab\x01\n" +
+	"\fWatchRequest\x12<\n" +
+	"\x06filter\x18\x01 \x03(\v2$.propmon.v1.WatchRequest.FilterEntryR\x06filter\x12\"\n" +
+	"\rlast_event_id\x18\x02 \x01(\x04R\vlastEventId\x1a9\n" +
+	"\vFilterEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe1\x02\n" +
+	"\bProposal\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12$\n" +
+	"\rcompatibility\x18\x02 \x01(\x05R\rcompatibility\x12\x1f\n" +
+	"\vprovider_id\x18\x03 \x01(\tR\n" +
+	"providerId\x12!\n" +
+	"\fservice_type\x18\x04 \x01(\tR\vserviceType\x120\n" +
+	"\blocation\x18\x05 \x01(\v2\x14.propmon.v1.LocationR\blocation\x12/\n" +
+	"\bcontacts\x18\x06 \x03(\v2\x13.propmon.v1.ContactR\bcontacts\x12-\n" +
+	"\aquality\x18\a \x01(\v2\x13.propmon.v1.QualityR\aquality\x12A\n" +
+	"\x0faccess_policies\x18\b \x03(\v2\x18.propmon.v1.AccessPolicyR\x0eaccessPolicies\"\xac\x01\n" +
+	"\bProvider\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\blocation\x18\x02 \x01(\v2\x14.propmon.v1.LocationR\blocation\x12-\n" +
+	"\aquality\x18\x03 \x01(\v2\x13.propmon.v1.QualityR\aquality\x12/\n" +
+	"\bservices\x18\x04 \x03(\v2\x13.propmon.v1.ServiceR\bservices\"\xc6\x01\n" +
+	"\aService\x12!\n" +
+	"\fservice_type\x18\x01 \x01(\tR\vserviceType\x12$\n" +
+	"\rcompatibility\x18\x02 \x01(\x05R\rcompatibility\x12/\n" +
+	"\bcontacts\x18\x03 \x03(\v2\x13.propmon.v1.ContactR\bcontacts\x12A\n" +
+	"\x0faccess_policies\x18\x04 \x03(\v2\x18.propmon.v1.AccessPolicyR\x0eaccessPolicies\"\xab\x01\n" +
+	"\bLocation\x12\x1c\n" +
+	"\tcontinent\x18\x01 \x01(\tR\tcontinent\x12\x18\n" +
+	"\acountry\x18\x02 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x03 \x01(\tR\x06region\x12\x12\n" +
+	"\x04city\x18\x04 \x01(\tR\x04city\x12\x10\n" +
+	"\x03asn\x18\x05 \x01(\x03R\x03asn\x12\x10\n" +
+	"\x03isp\x18\x06 \x01(\tR\x03isp\x12\x17\n" +
+	"\aip_type\x18\a \x01(\tR\x06ipType\"\\\n" +
+	"\aContact\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12=\n" +
+	"\n" +
+	"definition\x18\x02 \x01(\v2\x1d.propmon.v1.ContactDefinitionR\n" +
+	"definition\">\n" +
+	"\x11ContactDefinition\x12)\n" +
+	"\x10broker_addresses\x18\x01 \x03(\tR\x0fbrokerAddresses\"6\n" +
+	"\fAccessPolicy\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\"\x9c\x01\n" +
+	"\aQuality\x12\x18\n" +
+	"\aquality\x18\x01 \x01(\x01R\aquality\x12\x18\n" +
+	"\alatency\x18\x02 \x01(\x01R\alatency\x12\x1c\n" +
+	"\tbandwidth\x18\x03 \x01(\x01R\tbandwidth\x12\x16\n" +
+	"\x06uptime\x18\x04 \x01(\x01R\x06uptime\x12'\n" +
+	"\x0frestricted_node\x18\x05 \x01(\bR\x0erestrictedNode\"\xda\x02\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12*\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x16.propmon.v1.Event.TypeR\x04type\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x120\n" +
+	"\bproposal\x18\x04 \x01(\v2\x14.propmon.v1.ProposalR\bproposal\"\xb2\x01\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fTYPE_REGISTERED\x10\x01\x12\x1a\n" +
+	"\x16TYPE_PING_AFTER_EXPIRY\x10\x02\x12\x15\n" +
+	"\x11TYPE_UNREGISTERED\x10\x03\x12\x10\n" +
+	"\fTYPE_EXPIRED\x10\x04\x12\x10\n" +
+	"\fTYPE_EVICTED\x10\x05\x12\x18\n" +
+	"\x14TYPE_QUALITY_CHANGED\x10\x06\x12\x0e\n" +
+	"\n" +
+	"TYPE_RESET\x10\a2\xff\x02\n" +
+	"\x0fProposalService\x12T\n" +
+	"\rListProposals\x12 .propmon.v1.ListProposalsRequest\x1a!.propmon.v1.ListProposalsResponse\x12C\n" +
+	"\vGetProposal\x12\x1e.propmon.v1.GetProposalRequest\x1a\x14.propmon.v1.Proposal\x12T\n" +
+	"\rListProviders\x12 .propmon.v1.ListProvidersRequest\x1a!.propmon.v1.ListProvidersResponse\x12C\n" +
+	"\vGetProvider\x12\x1e.propmon.v1.GetProviderRequest\x1a\x14.propmon.v1.Provider\x126\n" +
+	"\x05Watch\x12\x18.propmon.v1.WatchRequest\x1a\x11.propmon.v1.Event0\x01B*Z(github.com/sch8ill/propmon/rpc/propmonpbb\x06proto3"
+
+var (
+	file_propmon_proto_rawDescOnce sync.Once
+	file_propmon_proto_rawDescData []byte
+)
+
+func file_propmon_proto_rawDescGZIP() []byte {
+	file_propmon_proto_rawDescOnce.Do(func() {
+		file_propmon_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_propmon_proto_rawDesc), len(file_propmon_proto_rawDesc)))
+	})
+	return file_propmon_proto_rawDescData
+}
+
+var file_propmon_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_propmon_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_propmon_proto_goTypes = []any{
+	(Event_Type)(0),               // 0: propmon.v1.Event.Type
+	(*ListProposalsRequest)(nil),  // 1: propmon.v1.ListProposalsRequest
+	(*ListProposalsResponse)(nil), // 2: propmon.v1.ListProposalsResponse
+	(*GetProposalRequest)(nil),    // 3: propmon.v1.GetProposalRequest
+	(*ListProvidersRequest)(nil),  // 4: propmon.v1.ListProvidersRequest
+	(*ListProvidersResponse)(nil), // 5: propmon.v1.ListProvidersResponse
+	(*GetProviderRequest)(nil),    // 6: propmon.v1.GetProviderRequest
+	(*WatchRequest)(nil),          // 7: propmon.v1.WatchRequest
+	(*Proposal)(nil),              // 8: propmon.v1.Proposal
+	(*Provider)(nil),              // 9: propmon.v1.Provider
+	(*Service)(nil),               // 10: propmon.v1.Service
+	(*Location)(nil),              // 11: propmon.v1.Location
+	(*Contact)(nil),               // 12: propmon.v1.Contact
+	(*ContactDefinition)(nil),     // 13: propmon.v1.ContactDefinition
+	(*AccessPolicy)(nil),          // 14: propmon.v1.AccessPolicy
+	(*Quality)(nil),               // 15: propmon.v1.Quality
+	(*Event)(nil),                 // 16: propmon.v1.Event
+	nil,                           // 17: propmon.v1.ListProposalsRequest.FilterEntry
+	nil,                           // 18: propmon.v1.ListProvidersRequest.FilterEntry
+	nil,                           // 19: propmon.v1.WatchRequest.FilterEntry
+	(*timestamppb.Timestamp)(nil), // 20: google.protobuf.Timestamp
+}
+var file_propmon_proto_depIdxs = []int32{
+	17, // 0: propmon.v1.ListProposalsRequest.filter:type_name -> propmon.v1.ListProposalsRequest.FilterEntry
+	8,  // 1: propmon.v1.ListProposalsResponse.proposals:type_name -> propmon.v1.Proposal
+	18, // 2: propmon.v1.ListProvidersRequest.filter:type_name -> propmon.v1.ListProvidersRequest.FilterEntry
+	9,  // 3: propmon.v1.ListProvidersResponse.providers:type_name -> propmon.v1.Provider
+	19, // 4: propmon.v1.WatchRequest.filter:type_name -> propmon.v1.WatchRequest.FilterEntry
+	11, // 5: propmon.v1.Proposal.location:type_name -> propmon.v1.Location
+	12, // 6: propmon.v1.Proposal.contacts:type_name -> propmon.v1.Contact
+	15, // 7: propmon.v1.Proposal.quality:type_name -> propmon.v1.Quality
+	14, // 8: propmon.v1.Proposal.access_policies:type_name -> propmon.v1.AccessPolicy
+	11, // 9: propmon.v1.Provider.location:type_name -> propmon.v1.Location
+	15, // 10: propmon.v1.Provider.quality:type_name -> propmon.v1.Quality
+	10, // 11: propmon.v1.Provider.services:type_name -> propmon.v1.Service
+	12, // 12: propmon.v1.Service.contacts:type_name -> propmon.v1.Contact
+	14, // 13: propmon.v1.Service.access_policies:type_name -> propmon.v1.AccessPolicy
+	13, // 14: propmon.v1.Contact.definition:type_name -> propmon.v1.ContactDefinition
+	0,  // 15: propmon.v1.Event.type:type_name -> propmon.v1.Event.Type
+	20, // 16: propmon.v1.Event.time:type_name -> google.protobuf.Timestamp
+	8,  // 17: propmon.v1.Event.proposal:type_name -> propmon.v1.Proposal
+	1,  // 18: propmon.v1.ProposalService.ListProposals:input_type -> propmon.v1.ListProposalsRequest
+	3,  // 19: propmon.v1.ProposalService.GetProposal:input_type -> propmon.v1.GetProposalRequest
+	4,  // 20: propmon.v1.ProposalService.ListProviders:input_type -> propmon.v1.ListProvidersRequest
+	6,  // 21: propmon.v1.ProposalService.GetProvider:input_type -> propmon.v1.GetProviderRequest
+	7,  // 22: propmon.v1.ProposalService.Watch:input_type -> propmon.v1.WatchRequest
+	2,  // 23: propmon.v1.ProposalService.ListProposals:output_type -> propmon.v1.ListProposalsResponse
+	8,  // 24: propmon.v1.ProposalService.GetProposal:output_type -> propmon.v1.Proposal
+	5,  // 25: propmon.v1.ProposalService.ListProviders:output_type -> propmon.v1.ListProvidersResponse
+	9,  // 26: propmon.v1.ProposalService.GetProvider:output_type -> propmon.v1.Provider
+	16, // 27: propmon.v1.ProposalService.Watch:output_type -> propmon.v1.Event
+	23, // [23:28] is the sub-list for method output_type
+	18, // [18:23] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_propmon_proto_init() }
+func file_propmon_proto_init() {
+	if File_propmon_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_propmon_proto_rawDesc), len(file_propmon_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_propmon_proto_goTypes,
+		DependencyIndexes: file_propmon_proto_depIdxs,
+		EnumInfos:         file_propmon_proto_enumTypes,
+		MessageInfos:      file_propmon_proto_msgTypes,
+	}.Build()
+	File_propmon_proto = out.File
+	file_propmon_proto_goTypes = nil
+	file_propmon_proto_depIdxs = nil
+}
@@ -0,0 +1,266 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/sch8ill/propmon/auth"
+	"github.com/sch8ill/propmon/proposal"
+	"github.com/sch8ill/propmon/rpc/propmonpb"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+type Options struct {
+	Address string
+	TLSCert string
+	TLSKey  string
+	// EventBufferSize is the number of events buffered per watching client before it is disconnected
+	EventBufferSize int
+	// APIKeys are required to grant the read:proposals scope, authentication is disabled without keys
+	APIKeys []auth.APIKey
+}
+
+// Server serves the proposal service over grpc.
+type Server struct {
+	propmonpb.UnimplementedProposalServiceServer
+	repository *proposal.Repository
+	options    Options
+	keyring    *auth.Keyring
+	server     *grpc.Server
+}
+
+func New(repository *proposal.Repository, options Options) (*Server, error) {
+	keyring, err := auth.NewKeyring(options.APIKeys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api keys: %w", err)
+	}
+
+	s := &Server{
+		repository: repository,
+		options:    options,
+		keyring:    keyring,
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.authorizeUnary),
+		grpc.ChainStreamInterceptor(s.authorizeStream),
+	}
+	if options.TLSCert != "" || options.TLSKey != "" {
+		creds, err := credentials.NewServerTLSFromFile(options.TLSCert, options.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load grpc tls certificate: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	s.server = grpc.NewServer(opts...)
+	propmonpb.RegisterProposalServiceServer(s.server, s)
+	reflection.Register(s.server)
+
+	return s, nil
+}
+
+func (s *Server) ListenAndServe() error {
+	lis, err := net.Listen("tcp", s.options.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	log.Info().Str("addr", s.options.Address).Bool("tls", s.options.TLSCert != "").Msg("Starting grpc server")
+	return s.Serve(lis)
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+// Stop closes all connections, including running watch streams.
+func (s *Server) Stop() {
+	s.server.Stop()
+}
+
+func (s *Server) authorizeUnary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) authorizeStream(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorize(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// authorize requires the api key sent as authorization or x-api-key metadata to grant the read:proposals scope.
+func (s *Server) authorize(ctx context.Context) error {
+	if s.keyring.Empty() {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var token string
+	if values := md.Get("x-api-key"); len(values) > 0 {
+		token = values[0]
+	} else if values := md.Get("authorization"); len(values) > 0 {
+		scheme, value, ok := strings.Cut(values[0], " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			token = strings.TrimSpace(value)
+		}
+	}
+	if token == "" {
+		return status.Error(codes.Unauthenticated, "api key required")
+	}
+
+	key, ok := s.keyring.Lookup(token)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "invalid api key")
+	}
+	if !key.HasScope(auth.ScopeReadProposals) {
+		return status.Errorf(codes.PermissionDenied, "api key lacks scope %s", auth.ScopeReadProposals)
+	}
+
+	return nil
+}
+
+func (s *Server) ListProposals(_ context.Context, req *propmonpb.ListProposalsRequest) (*propmonpb.ListProposalsResponse, error) {
+	filter, err := parseFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := proposal.ParseSort(req.GetSort())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	proposals, next, err := s.repository.Query(proposal.Query{
+		Filter: filter,
+		Sort:   sort,
+		Cursor: req.GetPageToken(),
+		Limit:  pageSize(req.GetPageSize()),
+	})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res := &propmonpb.ListProposalsResponse{
+		Proposals:     make([]*propmonpb.Proposal, 0, len(proposals)),
+		NextPageToken: next,
+	}
+	for _, p := range proposals {
+		res.Proposals = append(res.Proposals, toProposal(p))
+	}
+
+	return res, nil
+}
+
+func (s *Server) GetProposal(_ context.Context, req *propmonpb.GetProposalRequest) (*propmonpb.Proposal, error) {
+	p := s.repository.Get(req.GetKey())
+	if p == nil {
+		return nil, status.Errorf(codes.NotFound, "proposal %q not found", req.GetKey())
+	}
+	return toProposal(p), nil
+}
+
+// ListProviders lists providers ordered by ID, the page token is the ID of the last provider of the previous page.
+func (s *Server) ListProviders(_ context.Context, req *propmonpb.ListProvidersRequest) (*propmonpb.ListProvidersResponse, error) {
+	filter, err := parseFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	providers := s.repository.MatchingProviders(filter)
+
+	start := 0
+	if token := req.GetPageToken(); token != "" {
+		start, _ = slices.BinarySearchFunc(providers, token, func(p *proposal.Provider, id string) int {
+			if p.ID <= id {
+				return -1
+			}
+			return 1
+		})
+	}
+
+	end := min(start+pageSize(req.GetPageSize()), len(providers))
+
+	res := &propmonpb.ListProvidersResponse{Providers: make([]*propmonpb.Provider, 0, end-start)}
+	for _, p := range providers[start:end] {
+		res.Providers = append(res.Providers, toProvider(p))
+	}
+	if end < len(providers) {
+		res.NextPageToken = providers[end-1].ID
+	}
+
+	return res, nil
+}
+
+func (s *Server) GetProvider(_ context.Context, req *propmonpb.GetProviderRequest) (*propmonpb.Provider, error) {
+	p := s.repository.Provider(req.GetId())
+	if p == nil {
+		return nil, status.Errorf(codes.NotFound, "provider %q not found", req.GetId())
+	}
+	return toProvider(p), nil
+}
+
+func (s *Server) Watch(req *propmonpb.WatchRequest, stream grpc.ServerStreamingServer[propmonpb.Event]) error {
+	filter, err := parseFilter(req.GetFilter())
+	if err != nil {
+		return err
+	}
+
+	sub := s.repository.Subscribe(filter, req.GetLastEventId(), s.options.EventBufferSize)
+	defer sub.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+
+		case e, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "event buffer overflow")
+			}
+			if err := stream.Send(toEvent(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func parseFilter(filter map[string]string) (*proposal.Filter, error) {
+	query := make(url.Values, len(filter))
+	for key, value := range filter {
+		query.Set(key, value)
+	}
+
+	f, err := proposal.ParseFilter(query)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return f, nil
+}
+
+func pageSize(size int32) int {
+	if size <= 0 {
+		return defaultPageSize
+	}
+	return min(int(size), maxPageSize)
+}
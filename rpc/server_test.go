@@ -0,0 +1,290 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/sch8ill/propmon/auth"
+	"github.com/sch8ill/propmon/proposal"
+	"github.com/sch8ill/propmon/rpc/propmonpb"
+)
+
+func testProposals() []*proposal.Proposal {
+	return []*proposal.Proposal{
+		{ProviderID: "0xaaa", ServiceType: "wireguard", Compatibility: 2, Location: proposal.Location{Country: "DE", Asn: 3320},
+			Quality: &proposal.Quality{Quality: 2.5}},
+		{ProviderID: "0xaaa", ServiceType: "openvpn", Location: proposal.Location{Country: "DE", Asn: 3320}},
+		{ProviderID: "0xbbb", ServiceType: "wireguard", Location: proposal.Location{Country: "US"}},
+		{ProviderID: "0xccc", ServiceType: "wireguard", Location: proposal.Location{Country: "FR"}},
+	}
+}
+
+// newTestClient serves the repository on an in-memory listener.
+func newTestClient(t *testing.T, repository *proposal.Repository, options Options) propmonpb.ProposalServiceClient {
+	t.Helper()
+
+	s, err := New(repository, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return propmonpb.NewProposalServiceClient(conn)
+}
+
+func newTestRepository() *proposal.Repository {
+	repository := proposal.NewProposalRepository(time.Hour)
+	for _, p := range testProposals() {
+		repository.Store(p)
+	}
+	return repository
+}
+
+func TestListProposals(t *testing.T) {
+	client := newTestClient(t, newTestRepository(), Options{})
+
+	tests := []struct {
+		name string
+		req  *propmonpb.ListProposalsRequest
+		want []string
+	}{
+		{"all", &propmonpb.ListProposalsRequest{}, []string{"0xaaa.openvpn", "0xaaa.wireguard", "0xbbb.wireguard", "0xccc.wireguard"}},
+		{"filter", &propmonpb.ListProposalsRequest{Filter: map[string]string{"country": "DE,US", "service": "wireguard"}},
+			[]string{"0xaaa.wireguard", "0xbbb.wireguard"}},
+		{"sort", &propmonpb.ListProposalsRequest{Sort: "-quality", PageSize: 1}, []string{"0xaaa.wireguard"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := client.ListProposals(context.Background(), tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := proposalKeys(res.GetProposals()); !slices.Equal(got, tt.want) {
+				t.Errorf("proposals = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListProposalsPagination(t *testing.T) {
+	client := newTestClient(t, newTestRepository(), Options{})
+
+	var keys []string
+	req := &propmonpb.ListProposalsRequest{PageSize: 3}
+	for range 3 {
+		res, err := client.ListProposals(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, proposalKeys(res.GetProposals())...)
+		if res.GetNextPageToken() == "" {
+			break
+		}
+		req.PageToken = res.GetNextPageToken()
+	}
+
+	if want := []string{"0xaaa.openvpn", "0xaaa.wireguard", "0xbbb.wireguard", "0xccc.wireguard"}; !slices.Equal(keys, want) {
+		t.Errorf("proposals = %v, want %v", keys, want)
+	}
+}
+
+func TestGetProposal(t *testing.T) {
+	client := newTestClient(t, newTestRepository(), Options{})
+
+	p, err := client.GetProposal(context.Background(), &propmonpb.GetProposalRequest{Key: "0xaaa.wireguard"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.GetProviderId() != "0xaaa" || p.GetLocation().GetAsn() != 3320 || p.GetQuality().GetQuality() != 2.5 {
+		t.Errorf("proposal = %v", p)
+	}
+
+	_, err = client.GetProposal(context.Background(), &propmonpb.GetProposalRequest{Key: "0xddd.wireguard"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("error = %v, want NotFound", err)
+	}
+}
+
+func TestListProviders(t *testing.T) {
+	client := newTestClient(t, newTestRepository(), Options{})
+
+	var ids []string
+	req := &propmonpb.ListProvidersRequest{PageSize: 2}
+	for range 3 {
+		res, err := client.ListProviders(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range res.GetProviders() {
+			ids = append(ids, p.GetId())
+		}
+		if res.GetNextPageToken() == "" {
+			break
+		}
+		req.PageToken = res.GetNextPageToken()
+	}
+	if want := []string{"0xaaa", "0xbbb", "0xccc"}; !slices.Equal(ids, want) {
+		t.Errorf("providers = %v, want %v", ids, want)
+	}
+
+	res, err := client.ListProviders(context.Background(), &propmonpb.ListProvidersRequest{Filter: map[string]string{"country!": "DE"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.GetProviders()) != 2 {
+		t.Errorf("%d providers outside DE, want 2", len(res.GetProviders()))
+	}
+
+	_, err = client.ListProviders(context.Background(), &propmonpb.ListProvidersRequest{Filter: map[string]string{"asn": "x"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestGetProvider(t *testing.T) {
+	client := newTestClient(t, newTestRepository(), Options{})
+
+	p, err := client.GetProvider(context.Background(), &propmonpb.GetProviderRequest{Id: "0xaaa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var services []string
+	for _, s := range p.GetServices() {
+		services = append(services, s.GetServiceType())
+	}
+	slices.Sort(services)
+	if p.GetLocation().GetCountry() != "DE" || !slices.Equal(services, []string{"openvpn", "wireguard"}) {
+		t.Errorf("provider = %v", p)
+	}
+
+	_, err = client.GetProvider(context.Background(), &propmonpb.GetProviderRequest{Id: "0xddd"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("error = %v, want NotFound", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	repository := newTestRepository()
+	client := newTestClient(t, repository, Options{EventBufferSize: 16})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// resuming from the current event makes the test independent of when the server subscribes
+	stream, err := client.Watch(ctx, &propmonpb.WatchRequest{
+		Filter:      map[string]string{"country": "NL"},
+		LastEventId: repository.DebugStats().LastEventID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repository.Store(&proposal.Proposal{ProviderID: "0xddd", ServiceType: "wireguard", Location: proposal.Location{Country: "NL"}})
+	repository.Store(&proposal.Proposal{ProviderID: "0xeee", ServiceType: "wireguard", Location: proposal.Location{Country: "BE"}})
+	repository.Remove("0xddd.wireguard")
+
+	want := []propmonpb.Event_Type{propmonpb.Event_TYPE_REGISTERED, propmonpb.Event_TYPE_UNREGISTERED}
+	var lastID uint64
+	for _, typ := range want {
+		e, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.GetType() != typ || e.GetProposal().GetProviderId() != "0xddd" {
+			t.Errorf("event = %v, want %s of 0xddd", e, typ)
+		}
+		if e.GetId() <= lastID {
+			t.Errorf("event id %d is not increasing", e.GetId())
+		}
+		lastID = e.GetId()
+	}
+}
+
+func TestWatchReset(t *testing.T) {
+	client := newTestClient(t, newTestRepository(), Options{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &propmonpb.WatchRequest{LastEventId: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.GetType() != propmonpb.Event_TYPE_RESET || e.GetProposal() != nil {
+		t.Errorf("event = %v, want a reset", e)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	client := newTestClient(t, newTestRepository(), Options{APIKeys: []auth.APIKey{
+		{Name: "reader", Key: "read-key", Scopes: []auth.Scope{auth.ScopeReadProposals}},
+		{Name: "exporter", Key: "export-key", Scopes: []auth.Scope{auth.ScopeReadExport}},
+	}})
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		code codes.Code
+	}{
+		{"no key", nil, codes.Unauthenticated},
+		{"invalid key", metadata.Pairs("x-api-key", "wrong"), codes.Unauthenticated},
+		{"missing scope", metadata.Pairs("x-api-key", "export-key"), codes.PermissionDenied},
+		{"api key", metadata.Pairs("x-api-key", "read-key"), codes.OK},
+		{"bearer token", metadata.Pairs("authorization", "Bearer read-key"), codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewOutgoingContext(context.Background(), tt.md)
+			_, err := client.ListProposals(ctx, &propmonpb.ListProposalsRequest{})
+			if status.Code(err) != tt.code {
+				t.Errorf("error = %v, want %s", err, tt.code)
+			}
+
+			stream, err := client.Watch(ctx, &propmonpb.WatchRequest{LastEventId: 1})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if status.Code(err) != tt.code {
+				t.Errorf("watch error = %v, want %s", err, tt.code)
+			}
+		})
+	}
+}
+
+func proposalKeys(proposals []*propmonpb.Proposal) []string {
+	keys := make([]string, len(proposals))
+	for i, p := range proposals {
+		keys[i] = p.GetProviderId() + "." + p.GetServiceType()
+	}
+	return keys
+}
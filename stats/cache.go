@@ -0,0 +1,40 @@
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+// Cache serves the last computed stats until they are older than its ttl,
+// so repeated requests do not re-scan the repository.
+type Cache struct {
+	repository *proposal.Repository
+	ttl        time.Duration
+	stats      *Stats
+	mu         sync.Mutex
+}
+
+func NewCache(repository *proposal.Repository, ttl time.Duration) *Cache {
+	return &Cache{
+		repository: repository,
+		ttl:        ttl,
+	}
+}
+
+func (c *Cache) Get() *Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stats == nil || time.Since(c.stats.GeneratedAt) > c.ttl {
+		c.stats = Compute(c.repository.Proposals())
+	}
+
+	return c.stats
+}
+
+// TTL is the duration the stats are cached for.
+func (c *Cache) TTL() time.Duration {
+	return c.ttl
+}
@@ -0,0 +1,26 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func TestCache(t *testing.T) {
+	repo := proposal.NewProposalRepository(time.Hour)
+	repo.Store(&proposal.Proposal{ProviderID: "a", ServiceType: "wireguard"})
+
+	cache := NewCache(repo, time.Hour)
+	first := cache.Get()
+
+	repo.Store(&proposal.Proposal{ProviderID: "b", ServiceType: "wireguard"})
+	if got := cache.Get(); got != first || got.Providers != 1 {
+		t.Errorf("cached stats were recomputed before the ttl expired")
+	}
+
+	cache.ttl = 0
+	if got := cache.Get(); got.Providers != 2 {
+		t.Errorf("providers = %d after the ttl expired, want 2", got.Providers)
+	}
+}
@@ -0,0 +1,116 @@
+package stats
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+type Stats struct {
+	GeneratedAt  time.Time      `json:"generated_at"`
+	Proposals    int            `json:"proposals"`
+	Providers    int            `json:"providers"`
+	Countries    map[string]int `json:"countries"`
+	Continents   map[string]int `json:"continents"`
+	NodeTypes    map[string]int `json:"node_types"`
+	ServiceTypes map[string]int `json:"service_types"`
+	Asns         map[string]int `json:"asns"`
+	Isps         map[string]int `json:"isps"`
+	Quality      QualityStats   `json:"quality"`
+}
+
+type QualityStats struct {
+	Quality   Summary `json:"quality"`
+	Latency   Summary `json:"latency"`
+	Bandwidth Summary `json:"bandwidth"`
+	Uptime    Summary `json:"uptime"`
+}
+
+type Summary struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Avg   float64 `json:"avg"`
+	P50   float64 `json:"p50"`
+	P95   float64 `json:"p95"`
+	Max   float64 `json:"max"`
+}
+
+// Compute aggregates the proposals in a single pass. Service types are counted per proposal,
+// all other breakdowns and the quality summaries per provider.
+func Compute(proposals []*proposal.Proposal) *Stats {
+	s := &Stats{
+		GeneratedAt:  time.Now(),
+		Proposals:    len(proposals),
+		Countries:    make(map[string]int),
+		Continents:   make(map[string]int),
+		NodeTypes:    make(map[string]int),
+		ServiceTypes: make(map[string]int),
+		Asns:         make(map[string]int),
+		Isps:         make(map[string]int),
+	}
+
+	providers := make(map[string]struct{})
+	var quality, latency, bandwidth, uptime []float64
+
+	for _, p := range proposals {
+		s.ServiceTypes[p.ServiceType]++
+
+		if _, ok := providers[p.ProviderID]; ok {
+			continue
+		}
+		providers[p.ProviderID] = struct{}{}
+
+		s.Countries[p.Location.Country]++
+		s.Continents[p.Location.Continent]++
+		s.NodeTypes[p.Location.IpType]++
+		s.Asns[strconv.Itoa(p.Location.Asn)]++
+		s.Isps[p.Location.Isp]++
+
+		if p.Quality != nil {
+			quality = append(quality, p.Quality.Quality)
+			latency = append(latency, p.Quality.Latency)
+			bandwidth = append(bandwidth, p.Quality.Bandwidth)
+			uptime = append(uptime, p.Quality.Uptime)
+		}
+	}
+
+	s.Providers = len(providers)
+	s.Quality = QualityStats{
+		Quality:   summarize(quality),
+		Latency:   summarize(latency),
+		Bandwidth: summarize(bandwidth),
+		Uptime:    summarize(uptime),
+	}
+
+	return s
+}
+
+func summarize(values []float64) Summary {
+	if len(values) == 0 {
+		return Summary{}
+	}
+	slices.Sort(values)
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+
+	return Summary{
+		Count: len(values),
+		Min:   values[0],
+		Avg:   sum / float64(len(values)),
+		P50:   percentile(values, 0.5),
+		P95:   percentile(values, 0.95),
+		Max:   values[len(values)-1],
+	}
+}
+
+// percentile uses the nearest-rank method on sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
@@ -0,0 +1,113 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/sch8ill/propmon/proposal"
+)
+
+func TestCompute(t *testing.T) {
+	proposals := []*proposal.Proposal{
+		{
+			ProviderID:  "a",
+			ServiceType: "wireguard",
+			Location:    proposal.Location{Country: "DE", Continent: "EU", IpType: "residential", Asn: 3320, Isp: "DTAG"},
+			Quality:     &proposal.Quality{Quality: 1, Latency: 10, Bandwidth: 100, Uptime: 24},
+		},
+		// a second service of the same provider only counts towards the service types
+		{
+			ProviderID:  "a",
+			ServiceType: "openvpn",
+			Location:    proposal.Location{Country: "DE", Continent: "EU", IpType: "residential", Asn: 3320, Isp: "DTAG"},
+			Quality:     &proposal.Quality{Quality: 3, Latency: 30, Bandwidth: 300, Uptime: 24},
+		},
+		{
+			ProviderID:  "b",
+			ServiceType: "wireguard",
+			Location:    proposal.Location{Country: "US", Continent: "NA", IpType: "hosting", Asn: 16509, Isp: "Amazon"},
+			Quality:     &proposal.Quality{Quality: 3, Latency: 30, Bandwidth: 300, Uptime: 12},
+		},
+		{
+			ProviderID:  "c",
+			ServiceType: "wireguard",
+			Location:    proposal.Location{Country: "DE", Continent: "EU", IpType: "hosting", Asn: 24940, Isp: "Hetzner"},
+		},
+	}
+
+	s := Compute(proposals)
+
+	if s.Proposals != 4 || s.Providers != 3 {
+		t.Errorf("proposals = %d, providers = %d, want 4 and 3", s.Proposals, s.Providers)
+	}
+
+	counts := []struct {
+		name string
+		got  map[string]int
+		want map[string]int
+	}{
+		{"service types", s.ServiceTypes, map[string]int{"wireguard": 3, "openvpn": 1}},
+		{"countries", s.Countries, map[string]int{"DE": 2, "US": 1}},
+		{"continents", s.Continents, map[string]int{"EU": 2, "NA": 1}},
+		{"node types", s.NodeTypes, map[string]int{"residential": 1, "hosting": 2}},
+		{"asns", s.Asns, map[string]int{"3320": 1, "16509": 1, "24940": 1}},
+		{"isps", s.Isps, map[string]int{"DTAG": 1, "Amazon": 1, "Hetzner": 1}},
+	}
+	for _, c := range counts {
+		if len(c.got) != len(c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+			continue
+		}
+		for k, v := range c.want {
+			if c.got[k] != v {
+				t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+				break
+			}
+		}
+	}
+
+	want := Summary{Count: 2, Min: 1, Avg: 2, P50: 1, P95: 3, Max: 3}
+	if s.Quality.Quality != want {
+		t.Errorf("quality summary = %+v, want %+v", s.Quality.Quality, want)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   Summary
+	}{
+		{"empty", nil, Summary{}},
+		{"single", []float64{5}, Summary{Count: 1, Min: 5, Avg: 5, P50: 5, P95: 5, Max: 5}},
+		{"unsorted", []float64{4, 1, 3, 2}, Summary{Count: 4, Min: 1, Avg: 2.5, P50: 2, P95: 4, Max: 4}},
+	}
+
+	for _, tt := range tests {
+		if got := summarize(tt.values); got != tt.want {
+			t.Errorf("%s: summarize = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]float64, 20)
+	for i := range sorted {
+		sorted[i] = float64(i + 1)
+	}
+
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0, 1},
+		{0.5, 10},
+		{0.95, 19},
+		{1, 20},
+	}
+
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}